@@ -0,0 +1,22 @@
+data "purelymail_client_settings" "alice" {
+  user_name           = "alice@example.com"
+  display_name        = "Alice Example"
+  render_autoconfig   = true
+  render_mobileconfig = true
+}
+
+# Serve the autoconfig file at https://autoconfig.example.com/mail/config-v1.1.xml.
+resource "local_file" "autoconfig" {
+  filename = "${path.module}/autoconfig/mail/config-v1.1.xml"
+  content  = data.purelymail_client_settings.alice.autoconfig_xml
+}
+
+# Hand the configuration profile to Alice to install on their Apple devices.
+resource "local_file" "mobileconfig" {
+  filename = "${path.module}/profiles/alice.mobileconfig"
+  content  = data.purelymail_client_settings.alice.mobileconfig
+}
+
+output "imap_server" {
+  value = "${data.purelymail_client_settings.alice.imap.hostname}:${data.purelymail_client_settings.alice.imap.port}"
+}
@@ -0,0 +1,8 @@
+# List the domains that still fail a DNS check
+list "purelymail_domain" "unverified" {
+  provider = purelymail
+
+  config {
+    passes_dns_checks = false
+  }
+}
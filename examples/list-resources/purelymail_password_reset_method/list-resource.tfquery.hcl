@@ -0,0 +1,8 @@
+# List the phone numbers users can reset their passwords with
+list "purelymail_password_reset_method" "phones" {
+  provider = purelymail
+
+  config {
+    type = "phone"
+  }
+}
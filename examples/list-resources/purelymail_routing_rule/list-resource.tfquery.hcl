@@ -0,0 +1,11 @@
+# List the routing rules of a domain, leaving catch-all rules to
+# purelymail_catchall
+list "purelymail_routing_rule" "example" {
+  provider         = purelymail
+  include_resource = true
+
+  config {
+    domain_name = "example.com"
+    catchall    = false
+  }
+}
@@ -0,0 +1,9 @@
+# List the users of a domain, with their attributes for generating config
+list "purelymail_user" "example" {
+  provider         = purelymail
+  include_resource = true
+
+  config {
+    domain_name = "example.com"
+  }
+}
@@ -16,23 +16,19 @@ resource "purelymail_user" "alice" {
   require_two_factor_authentication = true
 
   # Password reset methods (required for 2FA)
-  password_reset_methods = [
-    {
+  password_reset_methods = {
+    "alice@recovery.example.com" = {
       type            = "email"
-      target          = "alice@recovery.example.com"
       description     = "Primary recovery email"
       allow_mfa_reset = true
     }
-  ]
+  }
 }
 
-# Create a routing rule - catch-all that routes all mail to alice
-resource "purelymail_routing_rule" "catch_all" {
+# Create a catch-all that routes all unmatched mail to alice
+resource "purelymail_catchall" "example" {
   domain_name      = purelymail_domain.example.name
-  prefix           = false
-  match_user       = "*"
   target_addresses = ["alice@example.com"]
-  catchall         = true
 }
 
 # Generate an app password for the user
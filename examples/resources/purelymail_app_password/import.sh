@@ -0,0 +1 @@
+terraform import purelymail_app_password.mobile "alice@example.com/<app password>"
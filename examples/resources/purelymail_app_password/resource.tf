@@ -9,6 +9,34 @@ resource "purelymail_app_password" "desktop" {
   user_handle = "bob@example.com"
 }
 
+# Replace the password every 90 days, and whenever the client configuration
+# changes. The new password is created before the old one is deleted.
+resource "purelymail_app_password" "imap" {
+  user_handle   = "alice@example.com"
+  name          = "IMAP Sync"
+  rotation_days = 90
+
+  keepers = {
+    client_config = "v2"
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+# Only keep the password encrypted in state. Decrypt it with:
+#   terraform output -raw ci_app_password | gpg --decrypt
+resource "purelymail_app_password" "ci" {
+  user_handle = "ci@example.com"
+  name        = "CI Notifications"
+  pgp_key     = "keybase:alice"
+}
+
+output "ci_app_password" {
+  value = purelymail_app_password.ci.encrypted_app_password
+}
+
 # Output the password (will be marked as sensitive)
 output "mobile_app_password" {
   value     = purelymail_app_password.mobile.app_password
@@ -0,0 +1,6 @@
+import {
+  to = purelymail_catchall.example
+  identity = {
+    domain_name = "example.com"
+  }
+}
@@ -0,0 +1 @@
+terraform import purelymail_catchall.example "example.com"
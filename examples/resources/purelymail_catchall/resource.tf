@@ -0,0 +1,12 @@
+# Deliver mail for any unknown address on the domain to the postmaster
+resource "purelymail_catchall" "example" {
+  domain_name      = "example.com"
+  target_addresses = ["postmaster@example.com"]
+}
+
+# Take over a catch-all that was configured outside of Terraform
+resource "purelymail_catchall" "legacy" {
+  domain_name      = "legacy.example.com"
+  target_addresses = ["archive@example.com"]
+  adopt_existing   = true
+}
@@ -0,0 +1,6 @@
+import {
+  to = purelymail_domain.example
+  identity = {
+    name = "example.com"
+  }
+}
@@ -10,8 +10,49 @@ resource "purelymail_domain" "with_reset" {
   symbolic_subaddressing = true
 }
 
-# Domain with DNS recheck
-resource "purelymail_domain" "recheck_dns" {
-  name        = "mail.company.com"
-  recheck_dns = true
+# Domain that rechecks DNS whenever its records change
+resource "purelymail_domain" "recheck" {
+  name = "mail.company.com"
+
+  recheck_triggers = {
+    mx    = "mailserver.purelymail.com"
+    dkim  = "purelymail1._domainkey.dkimroot.purelymail.com"
+    dmarc = "v=DMARC1; p=quarantine"
+  }
+}
+
+# Domain that waits until its DNS records have propagated
+resource "purelymail_domain" "wait_for_dns" {
+  name = "mail.example.org"
+
+  wait_for_dns {
+    require  = ["mx", "spf", "dkim", "dmarc"]
+    timeout  = "15m"
+    interval = "30s"
+  }
+
+  # Leave room for the DNS wait within the create timeout
+  timeouts {
+    create = "20m"
+  }
+}
+
+# Domain that is only added once its ownership proof and MX records resolve
+resource "purelymail_domain" "preflight" {
+  name               = "mail.example.net"
+  preflight_dns      = true
+  preflight_resolver = "1.1.1.1:53"
+  preflight_timeout  = "10m"
+}
+
+# Production domain that can't be destroyed or replaced by accident
+resource "purelymail_domain" "protected" {
+  name                = "corp.example.com"
+  deletion_protection = true
+}
+
+# Domain that was already added to the account outside of Terraform
+resource "purelymail_domain" "existing" {
+  name           = "legacy.example.com"
+  adopt_existing = true
 }
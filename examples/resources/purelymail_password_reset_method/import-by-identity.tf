@@ -0,0 +1,7 @@
+import {
+  to = purelymail_password_reset_method.example
+  identity = {
+    user_name = "alice@example.com"
+    target    = "alice@recovery.example.com"
+  }
+}
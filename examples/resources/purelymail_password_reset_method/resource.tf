@@ -5,3 +5,19 @@ resource "purelymail_password_reset_method" "example" {
   description     = "Recovery email for Alice"
   allow_mfa_reset = true
 }
+
+# Phone numbers need a country code and are sent in E.164 format
+resource "purelymail_password_reset_method" "phone" {
+  user_name   = "alice@example.com"
+  type        = "phone"
+  target      = "+1 (555) 123-4567"
+  description = "Recovery phone for Alice"
+}
+
+# Take over a reset method that already exists on the user
+resource "purelymail_password_reset_method" "existing" {
+  user_name      = "bob@example.com"
+  type           = "email"
+  target         = "bob@recovery.example.com"
+  adopt_existing = true
+}
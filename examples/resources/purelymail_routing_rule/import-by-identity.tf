@@ -0,0 +1,8 @@
+import {
+  to = purelymail_routing_rule.support
+  identity = {
+    domain_name = "example.com"
+    match_user  = "support"
+    prefix      = false
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = purelymail_user.sally
+  identity = {
+    user_name = "sally@example.com"
+  }
+}
@@ -1,13 +1,15 @@
-# Create a user with password
+# Create a user with password (only a salted hash is kept in state)
 resource "purelymail_user" "alice" {
   user_name = "alice@example.com"
   password  = "initial-password-123"
 }
 
-# Create a user with password_wo (remains in state for change tracking)
+# Create a user with password_wo (never stored in state). Bump
+# password_wo_version to send a new password.
 resource "purelymail_user" "bob" {
-  user_name   = "bob@example.com"
-  password_wo = "secure-password-456"
+  user_name           = "bob@example.com"
+  password_wo         = "secure-password-456"
+  password_wo_version = 1
 }
 
 # Create a user with custom settings and password reset methods
@@ -17,12 +19,69 @@ resource "purelymail_user" "charlie" {
   enable_search_indexing = true
 
   # Configure password reset methods
-  password_reset_methods = [
-    {
+  password_reset_methods = {
+    "charlie.backup@example.com" = {
       type            = "email"
-      target          = "charlie.backup@example.com"
       description     = "Backup email"
       allow_mfa_reset = true
     }
-  ]
+  }
+}
+
+# Create a user that can't be destroyed by accident
+resource "purelymail_user" "ceo" {
+  user_name           = "ceo@example.com"
+  password            = "yet-another-password-012"
+  deletion_protection = true
+}
+
+# Bring existing mailboxes under Terraform without importing each one
+resource "purelymail_user" "existing" {
+  for_each = toset(["sales@example.com", "support@example.com"])
+
+  user_name      = each.key
+  adopt_existing = true
+}
+
+# Keep a partially created user in state (tainted) instead of deleting it
+# when a later step such as enabling 2FA fails
+resource "purelymail_user" "keep_partial" {
+  user_name                         = "ops@example.com"
+  password                          = "ops-password-345"
+  require_two_factor_authentication = true
+  on_create_failure                 = "taint"
+
+  password_reset_methods = {
+    "ops.backup@example.com" = {
+      type = "email"
+    }
+  }
+}
+
+# Manage some password reset methods inline and others with standalone
+# purelymail_password_reset_method resources. In additive mode the user only
+# reads and changes the methods it declares.
+resource "purelymail_user" "standalone_methods" {
+  user_name                         = "finance@example.com"
+  require_two_factor_authentication = true
+  password_reset_methods_mode       = "additive"
+
+  password_reset_methods = {
+    "finance.backup@example.com" = {
+      type = "email"
+    }
+  }
+}
+
+resource "purelymail_password_reset_method" "finance_phone" {
+  user_name = purelymail_user.standalone_methods.user_name
+  type      = "phone"
+  target    = "+15555550100"
+}
+
+# Forward mail to the manager after the user is deleted. The routing rule ID is
+# reported in a warning on destroy, so the rule can be removed later.
+resource "purelymail_user" "leaver" {
+  user_name             = "leaver@example.com"
+  on_destroy_forward_to = ["manager@example.com"]
 }
@@ -16,19 +16,17 @@ resource "purelymail_user" "alice" {
   require_two_factor_authentication = true
 
   # Password reset methods - at least one is required for 2FA
-  password_reset_methods = [
-    {
+  password_reset_methods = {
+    "alice.recovery@example.com" = {
       type            = "email"
-      target          = "alice.recovery@example.com"
       description     = "Primary recovery email"
       allow_mfa_reset = true
-    },
-    {
+    }
+    "+15551234567" = {
       type            = "phone"
-      target          = "+15551234567"
       description     = "Recovery phone"
       allow_mfa_reset = false
     }
-  ]
+  }
 }
 
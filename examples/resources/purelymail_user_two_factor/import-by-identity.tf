@@ -0,0 +1,6 @@
+import {
+  to = purelymail_user_two_factor.alice
+  identity = {
+    user_name = "alice@example.com"
+  }
+}
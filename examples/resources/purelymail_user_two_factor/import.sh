@@ -0,0 +1 @@
+terraform import purelymail_user_two_factor.alice "alice@example.com"
@@ -0,0 +1,18 @@
+resource "purelymail_user" "alice" {
+  user_name                   = "alice@example.com"
+  password_reset_methods_mode = "ignore"
+}
+
+resource "purelymail_password_reset_method" "alice_email" {
+  user_name = purelymail_user.alice.user_name
+  type      = "email"
+  target    = "alice@recovery.example.com"
+}
+
+# Enable 2FA once the reset methods exist, and disable it before they are
+# destroyed
+resource "purelymail_user_two_factor" "alice" {
+  user_name = purelymail_user.alice.user_name
+
+  depends_on = [purelymail_password_reset_method.alice_email]
+}
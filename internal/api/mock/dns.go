@@ -0,0 +1,151 @@
+package mock
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSServer is a minimal authoritative DNS server for testing. It answers TXT
+// and MX queries over UDP from in-memory records.
+type DNSServer struct {
+	mu   sync.Mutex
+	conn net.PacketConn
+
+	// Records keyed by fully qualified, lower case name
+	txt map[string][]string
+	mx  map[string][]string
+}
+
+// NewDNSServer starts a DNS server listening on a random local UDP port.
+func NewDNSServer() (*DNSServer, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DNSServer{
+		conn: conn,
+		txt:  make(map[string][]string),
+		mx:   make(map[string][]string),
+	}
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns the host:port the server listens on.
+func (s *DNSServer) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// Close stops the server.
+func (s *DNSServer) Close() error {
+	return s.conn.Close()
+}
+
+// SetTXT replaces the TXT records of name.
+func (s *DNSServer) SetTXT(name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.txt[fqdn(name)] = values
+}
+
+// SetMX replaces the MX records of name. All records get the same preference.
+func (s *DNSServer) SetMX(name string, hosts ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mx[fqdn(name)] = hosts
+}
+
+func (s *DNSServer) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			// Closed
+			return
+		}
+
+		resp, err := s.answer(buf[:n])
+		if err != nil {
+			continue
+		}
+		_, _ = s.conn.WriteTo(resp, addr)
+	}
+}
+
+// answer builds the response to a single query message.
+func (s *DNSServer) answer(query []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	header, err := p.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	name := strings.ToLower(q.Name.String())
+	txt, hasTXT := s.txt[name]
+	mx, hasMX := s.mx[name]
+	s.mu.Unlock()
+
+	rcode := dnsmessage.RCodeSuccess
+	if !hasTXT && !hasMX {
+		rcode = dnsmessage.RCodeNameError
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:            header.ID,
+		Response:      true,
+		Authoritative: true,
+		RCode:         rcode,
+	})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+	switch q.Type {
+	case dnsmessage.TypeTXT:
+		for _, value := range txt {
+			if err := b.TXTResource(rh, dnsmessage.TXTResource{TXT: []string{value}}); err != nil {
+				return nil, err
+			}
+		}
+	case dnsmessage.TypeMX:
+		for _, host := range mx {
+			mxName, err := dnsmessage.NewName(fqdn(host))
+			if err != nil {
+				return nil, err
+			}
+			if err := b.MXResource(rh, dnsmessage.MXResource{Pref: 10, MX: mxName}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return b.Finish()
+}
+
+func fqdn(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
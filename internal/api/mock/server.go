@@ -2,10 +2,15 @@
 package mock
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
 )
@@ -22,6 +27,21 @@ type Server struct {
 	appPasswords   map[string]string                              // appPassword -> userHandle
 	passwordResets map[string][]api.ListPasswordResetResponseItem // userName -> slice of methods
 
+	// DNS state reported by rechecks, per domain
+	dnsStates map[string]DNSState
+
+	// Shared domains available to every account
+	sharedDomains []string
+
+	// Injected faults: operation -> successful calls left before it fails
+	faults map[string]int
+
+	// Injected delays: operation -> how long it takes before it is handled
+	delays map[string]time.Duration
+
+	// Whether CreateUser only accepts userName, like older API versions
+	basicCreateUser bool
+
 	// ID generators
 	nextRoutingRuleID int32
 	nextAppPasswordID int
@@ -33,6 +53,16 @@ type userState struct {
 	recoveryEnabled                bool
 	requireTwoFactorAuthentication bool
 	enableSpamFiltering            bool
+	password                       string
+	passwordChanges                int
+}
+
+// DNSState describes which DNS checks a domain passes when it is rechecked.
+type DNSState struct {
+	PassesMx    bool
+	PassesSpf   bool
+	PassesDkim  bool
+	PassesDmarc bool
 }
 
 // NewServer creates a new mock server with empty state.
@@ -43,30 +73,209 @@ func NewServer() *Server {
 		routingRules:      make(map[int32]api.RoutingRule),
 		appPasswords:      make(map[string]string),
 		passwordResets:    make(map[string][]api.ListPasswordResetResponseItem),
+		dnsStates:         make(map[string]DNSState),
+		faults:            make(map[string]int),
+		delays:            make(map[string]time.Duration),
 		nextRoutingRuleID: 1,
 		nextAppPasswordID: 1,
 	}
 }
 
+// SetDNSState controls the result of DNS rechecks for a domain, which may not
+// have been added yet. Without it, rechecks pass every check.
+func (s *Server) SetDNSState(domainName string, state DNSState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dnsStates[strings.ToLower(domainName)] = state
+}
+
+// AddSharedDomain adds a domain that is shared with the account, like
+// Purelymail's own domains. It is only listed when shared domains are included.
+func (s *Server) AddSharedDomain(domainName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sharedDomains = append(s.sharedDomains, domainName)
+}
+
+// AddAppPassword adds an app password for a user, like one that was created
+// outside of Terraform.
+func (s *Server) AddAppPassword(userHandle string, appPassword string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.appPasswords[appPassword] = userHandle
+}
+
+// HasAppPassword reports whether an app password exists.
+func (s *Server) HasAppPassword(appPassword string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.appPasswords[appPassword]
+	return ok
+}
+
+// InjectFault makes an operation, named like its ServerInterface method (e.g.
+// "ModifyUser"), fail once with a server error after skip more successful calls.
+func (s *Server) InjectFault(operation string, skip int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[operation] = skip
+}
+
+// ClearFaults removes the injected faults that haven't fired yet.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.faults)
+}
+
+// SetDelay makes every call of an operation, named like its ServerInterface
+// method, wait for d before it is handled, or until the client gives up. A
+// zero d removes the delay.
+func (s *Server) SetDelay(operation string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d == 0 {
+		delete(s.delays, operation)
+		return
+	}
+	s.delays[operation] = d
+}
+
+// SetBasicCreateUser makes CreateUser reject requests with any field besides
+// userName, so settings have to be applied with ModifyUser afterwards.
+func (s *Server) SetBasicCreateUser(basic bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.basicCreateUser = basic
+}
+
+// UserPassword returns the current password of a user and how many times it
+// has been set.
+func (s *Server) UserPassword(userName string) (string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.users[userKey(userName)]
+	return user.password, user.passwordChanges
+}
+
+// userKey returns the user name as stored, in lowercase like Purelymail
+// stores it, as it doesn't tell user names apart by case.
+func userKey(userName string) string {
+	return strings.ToLower(userName)
+}
+
+// fault waits for the injected delay of operation and reports whether an
+// injected fault fires for it, and writes the error response if so. It also
+// reports true without a response if the client gave up during the delay. The
+// caller must hold s.mu, which is released while waiting.
+func (s *Server) fault(w http.ResponseWriter, r *http.Request, operation string) bool {
+	if d, ok := s.delays[operation]; ok {
+		// The server only notices that the client gave up once the body is read
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Unlock()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(d):
+		}
+		s.mu.Lock()
+
+		if r.Context().Err() != nil {
+			return true
+		}
+	}
+
+	skip, ok := s.faults[operation]
+	if !ok {
+		return false
+	}
+	if skip > 0 {
+		s.faults[operation] = skip - 1
+		return false
+	}
+
+	delete(s.faults, operation)
+	http.Error(w, "injected fault", http.StatusInternalServerError)
+	return true
+}
+
 // User Management
 
 func (s *Server) CreateUser(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "CreateUser") {
+		return
+	}
+
 	var req api.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
+
+	if _, exists := s.users[req.UserName]; exists {
+		http.Error(w, "user already exists", http.StatusBadRequest)
+		return
+	}
+
+	if s.basicCreateUser && req != (api.CreateUserRequest{UserName: req.UserName}) {
+		http.Error(w, "unknown field in createUser request", http.StatusBadRequest)
+		return
+	}
 
 	// Initialize user with defaults
-	s.users[req.UserName] = userState{
+	user := userState{
 		enableSearchIndexing:           true,
 		recoveryEnabled:                false,
 		requireTwoFactorAuthentication: false,
 		enableSpamFiltering:            true,
 	}
+	if req.EnableSearchIndexing != nil {
+		user.enableSearchIndexing = *req.EnableSearchIndexing
+	}
+	if req.EnablePasswordReset != nil {
+		user.recoveryEnabled = *req.EnablePasswordReset
+	}
+	if req.Password != nil {
+		user.password = *req.Password
+		user.passwordChanges++
+	}
+	s.users[req.UserName] = user
+
+	// Recovery addresses become password reset methods, but only with
+	// password reset enabled
+	allowMfaReset := false
+	if req.RecoveryEmail != nil && user.recoveryEnabled {
+		methodType := api.Email
+		s.passwordResets[req.UserName] = append(s.passwordResets[req.UserName], api.ListPasswordResetResponseItem{
+			Type:          &methodType,
+			Target:        req.RecoveryEmail,
+			Description:   req.RecoveryEmailDescription,
+			AllowMfaReset: &allowMfaReset,
+		})
+	}
+	if req.RecoveryPhone != nil && user.recoveryEnabled {
+		methodType := api.Phone
+		s.passwordResets[req.UserName] = append(s.passwordResets[req.UserName], api.ListPasswordResetResponseItem{
+			Type:          &methodType,
+			Target:        req.RecoveryPhone,
+			Description:   req.RecoveryPhoneDescription,
+			AllowMfaReset: &allowMfaReset,
+		})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -77,11 +286,16 @@ func (s *Server) ModifyUser(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "ModifyUser") {
+		return
+	}
+
 	var req api.ModifyUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
 
 	user, exists := s.users[req.UserName]
 	if !exists {
@@ -97,8 +311,17 @@ func (s *Server) ModifyUser(w http.ResponseWriter, r *http.Request) {
 		user.recoveryEnabled = *req.EnablePasswordReset
 	}
 	if req.RequireTwoFactorAuthentication != nil {
+		// Like Purelymail, refuse 2FA for users without a way to reset it
+		if *req.RequireTwoFactorAuthentication && len(s.passwordResets[req.UserName]) == 0 {
+			http.Error(w, "two-factor authentication requires a password reset method", http.StatusBadRequest)
+			return
+		}
 		user.requireTwoFactorAuthentication = *req.RequireTwoFactorAuthentication
 	}
+	if req.NewPassword != nil {
+		user.password = *req.NewPassword
+		user.passwordChanges++
+	}
 
 	s.users[req.UserName] = user
 
@@ -111,11 +334,16 @@ func (s *Server) GetUser(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "GetUser") {
+		return
+	}
+
 	var req api.GetUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
 
 	user, exists := s.users[req.UserName]
 	if !exists {
@@ -146,11 +374,16 @@ func (s *Server) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "DeleteUser") {
+		return
+	}
+
 	var req api.DeleteUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
 
 	delete(s.users, req.UserName)
 	delete(s.passwordResets, req.UserName)
@@ -161,7 +394,30 @@ func (s *Server) DeleteUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) ListUsers(w http.ResponseWriter, r *http.Request) {
-	http.Error(w, "not implemented", http.StatusNotImplemented)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fault(w, r, "ListUsers") {
+		return
+	}
+
+	users := make([]string, 0, len(s.users))
+	for userName := range s.users {
+		users = append(users, userName)
+	}
+	sort.Strings(users)
+
+	resp := api.ListUserResponse{
+		Result: &struct {
+			Users *[]string `json:"users,omitempty"`
+		}{
+			Users: &users,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 // Domain Management
@@ -170,12 +426,23 @@ func (s *Server) AddDomain(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "AddDomain") {
+		return
+	}
+
 	var req api.AddDomainRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// Domain names are stored in lowercase, like Purelymail does
+	domainName := strings.ToLower(req.DomainName)
+	if _, exists := s.domains[domainName]; exists {
+		http.Error(w, "domain already exists", http.StatusBadRequest)
+		return
+	}
+
 	// Create domain with default settings and DNS summary
 	passesMx := true
 	passesSpf := true
@@ -185,8 +452,8 @@ func (s *Server) AddDomain(w http.ResponseWriter, r *http.Request) {
 	symbolicSubaddressing := false
 	isShared := false
 
-	s.domains[req.DomainName] = api.ApiDomainInfo{
-		Name:                  &req.DomainName,
+	s.domains[domainName] = api.ApiDomainInfo{
+		Name:                  &domainName,
 		AllowAccountReset:     &allowAccountReset,
 		SymbolicSubaddressing: &symbolicSubaddressing,
 		IsShared:              &isShared,
@@ -208,13 +475,17 @@ func (s *Server) DeleteDomain(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "DeleteDomain") {
+		return
+	}
+
 	var req api.DeleteDomainRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	delete(s.domains, req.Name)
+	delete(s.domains, strings.ToLower(req.Name))
 
 	result := make(map[string]interface{})
 	w.Header().Set("Content-Type", "application/json")
@@ -226,13 +497,18 @@ func (s *Server) UpdateDomainSettings(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "UpdateDomainSettings") {
+		return
+	}
+
 	var req api.UpdateDomainSettingsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	domain, exists := s.domains[req.Name]
+	domainName := strings.ToLower(req.Name)
+	domain, exists := s.domains[domainName]
 	if !exists {
 		http.Error(w, "domain not found", http.StatusNotFound)
 		return
@@ -246,13 +522,17 @@ func (s *Server) UpdateDomainSettings(w http.ResponseWriter, r *http.Request) {
 		domain.SymbolicSubaddressing = req.SymbolicSubaddressing
 	}
 
-	// If recheckDns is true, simulate DNS checks passing
+	// If recheckDns is true, simulate DNS checks passing unless a DNS state was set
 	if req.RecheckDns != nil && *req.RecheckDns {
 		if domain.DnsSummary != nil {
-			passesMx := true
-			passesSpf := true
-			passesDkim := true
-			passesDmarc := true
+			state, ok := s.dnsStates[domainName]
+			if !ok {
+				state = DNSState{PassesMx: true, PassesSpf: true, PassesDkim: true, PassesDmarc: true}
+			}
+			passesMx := state.PassesMx
+			passesSpf := state.PassesSpf
+			passesDkim := state.PassesDkim
+			passesDmarc := state.PassesDmarc
 			domain.DnsSummary = &api.ApiDomainDnsSummary{
 				PassesMx:    &passesMx,
 				PassesSpf:   &passesSpf,
@@ -262,7 +542,7 @@ func (s *Server) UpdateDomainSettings(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.domains[req.Name] = domain
+	s.domains[domainName] = domain
 
 	result := make(map[string]interface{})
 	w.Header().Set("Content-Type", "application/json")
@@ -274,11 +554,28 @@ func (s *Server) ListDomains(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "ListDomains") {
+		return
+	}
+
+	var req api.ListDomainsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var domains []api.ApiDomainInfo
 	for _, domain := range s.domains {
 		domainCopy := domain
 		domains = append(domains, domainCopy)
 	}
+	if req.IncludeShared != nil && *req.IncludeShared {
+		isShared := true
+		for _, name := range s.sharedDomains {
+			nameCopy := name
+			domains = append(domains, api.ApiDomainInfo{Name: &nameCopy, IsShared: &isShared})
+		}
+	}
 
 	resp := api.ListDomainsResponse{
 		Result: &struct {
@@ -311,6 +608,10 @@ func (s *Server) CreateRoutingRule(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "CreateRoutingRule") {
+		return
+	}
+
 	var req api.CreateRoutingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -329,7 +630,9 @@ func (s *Server) CreateRoutingRule(w http.ResponseWriter, r *http.Request) {
 		TargetAddresses: &req.TargetAddresses,
 	}
 
-	if req.Prefix {
+	if req.Catchall != nil {
+		rule.Catchall = req.Catchall
+	} else if req.Prefix {
 		rule.Catchall = nil
 	} else {
 		catchall := req.MatchUser == "*"
@@ -351,6 +654,10 @@ func (s *Server) DeleteRoutingRule(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "DeleteRoutingRule") {
+		return
+	}
+
 	var req api.DeleteRoutingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -369,6 +676,10 @@ func (s *Server) ListRoutingRules(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "ListRoutingRules") {
+		return
+	}
+
 	var rules []api.RoutingRule
 	for _, rule := range s.routingRules {
 		ruleCopy := rule
@@ -394,6 +705,10 @@ func (s *Server) CreateAppPassword(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "CreateAppPassword") {
+		return
+	}
+
 	var req api.CreateAppPassword
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -422,6 +737,10 @@ func (s *Server) DeleteAppPassword(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "DeleteAppPassword") {
+		return
+	}
+
 	var req api.DeleteAppPasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -442,11 +761,16 @@ func (s *Server) CreateOrUpdatePasswordResetMethod(w http.ResponseWriter, r *htt
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "CreateOrUpdatePasswordResetMethod") {
+		return
+	}
+
 	var req api.UpsertPasswordResetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
 
 	if s.passwordResets[req.UserName] == nil {
 		s.passwordResets[req.UserName] = []api.ListPasswordResetResponseItem{}
@@ -459,10 +783,14 @@ func (s *Server) CreateOrUpdatePasswordResetMethod(w http.ResponseWriter, r *htt
 		AllowMfaReset: req.AllowMfaReset,
 	}
 
-	// Update or create the method
+	// Update or create the method, an existing target may be renamed
+	existingTarget := req.Target
+	if req.ExistingTarget != nil {
+		existingTarget = *req.ExistingTarget
+	}
 	found := false
 	for i, m := range s.passwordResets[req.UserName] {
-		if m.Target != nil && *m.Target == req.Target {
+		if m.Target != nil && *m.Target == existingTarget {
 			s.passwordResets[req.UserName][i] = method
 			found = true
 			break
@@ -482,13 +810,24 @@ func (s *Server) DeletePasswordResetMethod(w http.ResponseWriter, r *http.Reques
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "DeletePasswordResetMethod") {
+		return
+	}
+
 	var req api.DeletePasswordResetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
 
 	if userMethods, exists := s.passwordResets[req.UserName]; exists {
+		// The last method can't be removed while 2FA is required
+		if len(userMethods) == 1 && s.users[req.UserName].requireTwoFactorAuthentication {
+			http.Error(w, "two-factor authentication requires a password reset method", http.StatusBadRequest)
+			return
+		}
+
 		// Remove the method with matching target
 		for i, m := range userMethods {
 			if m.Target != nil && *m.Target == req.Target {
@@ -511,11 +850,16 @@ func (s *Server) ListPasswordResetMethods(w http.ResponseWriter, r *http.Request
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.fault(w, r, "ListPasswordResetMethods") {
+		return
+	}
+
 	var req api.ListPasswordResetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.UserName = userKey(req.UserName)
 
 	var methods []api.ListPasswordResetResponseItem
 	if userMethods, exists := s.passwordResets[req.UserName]; exists {
@@ -23,6 +23,12 @@ const (
 // ServerUrlHttpspurelymailCom defines the Server URL for
 const ServerUrlHttpspurelymailCom = "https://purelymail.com"
 
+// Defines values for UserPasswordResetMethodType.
+const (
+	Email UserPasswordResetMethodType = "email"
+	Phone UserPasswordResetMethodType = "phone"
+)
+
 // AddDomainRequest defines model for AddDomainRequest.
 type AddDomainRequest struct {
 	DomainName string `json:"domainName"`
@@ -76,7 +82,15 @@ type CreateRoutingRequest struct {
 
 // CreateUserRequest defines model for CreateUserRequest.
 type CreateUserRequest struct {
-	UserName string `json:"userName"`
+	EnablePasswordReset      *bool   `json:"enablePasswordReset,omitempty"`
+	EnableSearchIndexing     *bool   `json:"enableSearchIndexing,omitempty"`
+	Password                 *string `json:"password,omitempty"`
+	RecoveryEmail            *string `json:"recoveryEmail,omitempty"`
+	RecoveryEmailDescription *string `json:"recoveryEmailDescription,omitempty"`
+	RecoveryPhone            *string `json:"recoveryPhone,omitempty"`
+	RecoveryPhoneDescription *string `json:"recoveryPhoneDescription,omitempty"`
+	SendWelcomeEmail         *bool   `json:"sendWelcomeEmail,omitempty"`
+	UserName                 string  `json:"userName"`
 }
 
 // DeleteAppPasswordRequest defines model for DeleteAppPasswordRequest.
@@ -171,10 +185,10 @@ type ListPasswordResetResponse struct {
 
 // ListPasswordResetResponseItem defines model for ListPasswordResetResponseItem.
 type ListPasswordResetResponseItem struct {
-	AllowMfaReset *bool   `json:"allowMfaReset,omitempty"`
-	Description   *string `json:"description,omitempty"`
-	Target        *string `json:"target,omitempty"`
-	Type          *string `json:"type,omitempty"`
+	AllowMfaReset *bool                        `json:"allowMfaReset,omitempty"`
+	Description   *string                      `json:"description,omitempty"`
+	Target        *string                      `json:"target,omitempty"`
+	Type          *UserPasswordResetMethodType `json:"type,omitempty"`
 }
 
 // ListRoutingResponse defines model for ListRoutingResponse.
@@ -221,16 +235,16 @@ type UpdateDomainSettingsRequest struct {
 
 // UpsertPasswordResetRequest defines model for UpsertPasswordResetRequest.
 type UpsertPasswordResetRequest struct {
-	AllowMfaReset  *bool   `json:"allowMfaReset,omitempty"`
-	Description    *string `json:"description,omitempty"`
-	ExistingTarget *string `json:"existingTarget,omitempty"`
-	Target         string  `json:"target"`
-	Type           string  `json:"type"`
-	UserName       string  `json:"userName"`
+	AllowMfaReset  *bool                       `json:"allowMfaReset,omitempty"`
+	Description    *string                     `json:"description,omitempty"`
+	ExistingTarget *string                     `json:"existingTarget,omitempty"`
+	Target         string                      `json:"target"`
+	Type           UserPasswordResetMethodType `json:"type"`
+	UserName       string                      `json:"userName"`
 }
 
 // UserPasswordResetMethodType defines model for UserPasswordResetMethodType.
-type UserPasswordResetMethodType = map[string]interface{}
+type UserPasswordResetMethodType string
 
 // AddDomainJSONRequestBody defines body for AddDomain for application/json ContentType.
 type AddDomainJSONRequestBody = AddDomainRequest
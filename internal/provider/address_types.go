@@ -0,0 +1,444 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/net/idna"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ basetypes.StringTypable = DomainNameType{}
+var _ basetypes.StringValuableWithSemanticEquals = DomainNameValue{}
+var _ xattr.ValidateableAttribute = DomainNameValue{}
+var _ basetypes.StringTypable = EmailAddressType{}
+var _ basetypes.StringValuableWithSemanticEquals = EmailAddressValue{}
+var _ xattr.ValidateableAttribute = EmailAddressValue{}
+var _ basetypes.StringTypable = MailboxNameType{}
+var _ basetypes.StringValuableWithSemanticEquals = MailboxNameValue{}
+var _ xattr.ValidateableAttribute = MailboxNameValue{}
+
+// domainNameProfile maps domain names like idna.Lookup, and additionally
+// rejects empty labels and names that are too long for DNS.
+var domainNameProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+// normalizeDomainName returns the lowercase ASCII (punycode) form of a domain
+// name without a trailing dot, so that "Bücher.Example." and
+// "xn--bcher-kva.example" are equal.
+func normalizeDomainName(name string) (string, error) {
+	ascii, err := domainNameProfile.ToASCII(strings.TrimSuffix(name, "."))
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(ascii, ".") {
+		return "", fmt.Errorf("%q has no top-level domain", name)
+	}
+
+	return ascii, nil
+}
+
+// normalizeEmailAddress normalizes the domain part of an email address. The
+// local part is case sensitive in principle and kept as is. User names without
+// a domain part are allowed, as Purelymail accepts them for user handles.
+func normalizeEmailAddress(address string) (string, error) {
+	if address == "" || strings.ContainsAny(address, " \t\r\n") {
+		return "", fmt.Errorf("%q is not an email address", address)
+	}
+
+	localPart, domainName, ok := strings.Cut(address, "@")
+	if !ok {
+		return address, nil
+	}
+	if localPart == "" || strings.Contains(domainName, "@") {
+		return "", fmt.Errorf("%q is not an email address", address)
+	}
+
+	domainName, err := normalizeDomainName(domainName)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain in %q: %w", address, err)
+	}
+
+	return localPart + "@" + domainName, nil
+}
+
+// normalizeMailboxName normalizes a Purelymail user name. Purelymail doesn't
+// tell user names apart by case, so unlike normalizeEmailAddress the local
+// part is folded to lowercase as well.
+func normalizeMailboxName(name string) (string, error) {
+	normalized, err := normalizeEmailAddress(name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(normalized), nil
+}
+
+// sameDomainName reports whether two domain names are equal after
+// normalization.
+func sameDomainName(a, b string) bool {
+	return semanticallyEqual(normalizeDomainName, a, b)
+}
+
+// semanticallyEqual compares two values after normalization. Values that
+// can't be normalized are compared as is.
+func semanticallyEqual(normalize func(string) (string, error), a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	normalizedA, errA := normalize(a)
+	normalizedB, errB := normalize(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	return normalizedA == normalizedB
+}
+
+// normalizedOrRaw returns the normalized form of value, or value itself if it
+// can't be normalized. Resource identities use it, so changing only the
+// spelling of an attribute doesn't change the identity.
+func normalizedOrRaw(normalize func(string) (string, error), value string) string {
+	normalized, err := normalize(value)
+	if err != nil {
+		return value
+	}
+
+	return normalized
+}
+
+// DomainNameType is a string type for domain names that ignores differences in
+// case, a trailing dot, and Unicode versus punycode spelling.
+type DomainNameType struct {
+	basetypes.StringType
+}
+
+func (t DomainNameType) String() string {
+	return "DomainNameType"
+}
+
+func (t DomainNameType) Equal(o attr.Type) bool {
+	other, ok := o.(DomainNameType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t DomainNameType) ValueType(ctx context.Context) attr.Value {
+	return DomainNameValue{}
+}
+
+func (t DomainNameType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return DomainNameValue{StringValue: in}, nil
+}
+
+func (t DomainNameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return DomainNameValue{StringValue: stringValue}, nil
+}
+
+// DomainNameValue is a value of DomainNameType.
+type DomainNameValue struct {
+	basetypes.StringValue
+}
+
+// NewDomainNameValue returns a known domain name.
+func NewDomainNameValue(name string) DomainNameValue {
+	return DomainNameValue{StringValue: basetypes.NewStringValue(name)}
+}
+
+// NewDomainNameNull returns a null domain name.
+func NewDomainNameNull() DomainNameValue {
+	return DomainNameValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v DomainNameValue) Type(ctx context.Context) attr.Type {
+	return DomainNameType{}
+}
+
+func (v DomainNameValue) Equal(o attr.Value) bool {
+	other, ok := o.(DomainNameValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v DomainNameValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(DomainNameValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected DomainNameValue, got: %T. Please report this issue to the provider developers.", newValuable))
+		return false, diags
+	}
+
+	return sameDomainName(v.ValueString(), newValue.ValueString()), diags
+}
+
+func (v DomainNameValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := normalizeDomainName(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Domain Name",
+			fmt.Sprintf("Attribute %s must be a domain name such as \"example.com\": %s", req.Path, err),
+		)
+	}
+}
+
+// EmailAddressType is a string type for email addresses whose domain part is
+// compared like DomainNameType.
+type EmailAddressType struct {
+	basetypes.StringType
+}
+
+func (t EmailAddressType) String() string {
+	return "EmailAddressType"
+}
+
+func (t EmailAddressType) Equal(o attr.Type) bool {
+	other, ok := o.(EmailAddressType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t EmailAddressType) ValueType(ctx context.Context) attr.Value {
+	return EmailAddressValue{}
+}
+
+func (t EmailAddressType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return EmailAddressValue{StringValue: in}, nil
+}
+
+func (t EmailAddressType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return EmailAddressValue{StringValue: stringValue}, nil
+}
+
+// EmailAddressValue is a value of EmailAddressType.
+type EmailAddressValue struct {
+	basetypes.StringValue
+}
+
+// NewEmailAddressValue returns a known email address.
+func NewEmailAddressValue(address string) EmailAddressValue {
+	return EmailAddressValue{StringValue: basetypes.NewStringValue(address)}
+}
+
+// NewEmailAddressNull returns a null email address.
+func NewEmailAddressNull() EmailAddressValue {
+	return EmailAddressValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v EmailAddressValue) Type(ctx context.Context) attr.Type {
+	return EmailAddressType{}
+}
+
+func (v EmailAddressValue) Equal(o attr.Value) bool {
+	other, ok := o.(EmailAddressValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v EmailAddressValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(EmailAddressValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected EmailAddressValue, got: %T. Please report this issue to the provider developers.", newValuable))
+		return false, diags
+	}
+
+	return semanticallyEqual(normalizeEmailAddress, v.ValueString(), newValue.ValueString()), diags
+}
+
+func (v EmailAddressValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := normalizeEmailAddress(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Email Address",
+			fmt.Sprintf("Attribute %s must be an email address such as \"alice@example.com\": %s", req.Path, err),
+		)
+	}
+}
+
+// MailboxNameType is a string type for Purelymail user names, which are
+// compared ignoring case and like DomainNameType in the domain part.
+type MailboxNameType struct {
+	basetypes.StringType
+}
+
+func (t MailboxNameType) String() string {
+	return "MailboxNameType"
+}
+
+func (t MailboxNameType) Equal(o attr.Type) bool {
+	other, ok := o.(MailboxNameType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t MailboxNameType) ValueType(ctx context.Context) attr.Value {
+	return MailboxNameValue{}
+}
+
+func (t MailboxNameType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return MailboxNameValue{StringValue: in}, nil
+}
+
+func (t MailboxNameType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return MailboxNameValue{StringValue: stringValue}, nil
+}
+
+// MailboxNameValue is a value of MailboxNameType.
+type MailboxNameValue struct {
+	basetypes.StringValue
+}
+
+// NewMailboxNameValue returns a known user name.
+func NewMailboxNameValue(address string) MailboxNameValue {
+	return MailboxNameValue{StringValue: basetypes.NewStringValue(address)}
+}
+
+// NewMailboxNameNull returns a null user name.
+func NewMailboxNameNull() MailboxNameValue {
+	return MailboxNameValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v MailboxNameValue) Type(ctx context.Context) attr.Type {
+	return MailboxNameType{}
+}
+
+func (v MailboxNameValue) Equal(o attr.Value) bool {
+	other, ok := o.(MailboxNameValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v MailboxNameValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(MailboxNameValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected MailboxNameValue, got: %T. Please report this issue to the provider developers.", newValuable))
+		return false, diags
+	}
+
+	return semanticallyEqual(normalizeMailboxName, v.ValueString(), newValue.ValueString()), diags
+}
+
+func (v MailboxNameValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := normalizeMailboxName(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Email Address",
+			fmt.Sprintf("Attribute %s must be an email address such as \"alice@example.com\": %s", req.Path, err),
+		)
+	}
+}
+
+// keepPriorSpelling sets an attribute that uses
+// requiresReplaceUnlessSemanticallyEqual back to its prior value in Update. It
+// can only have changed in spelling there, and Purelymail still knows the
+// object by its prior spelling, so the object is addressed with that. The
+// planned spelling still ends up in state, as the framework keeps planned
+// values that are semantically equal to the applied ones.
+func keepPriorSpelling[T basetypes.StringValuableWithSemanticEquals](planned *T, prior T) {
+	*planned = prior
+}
+
+// requiresReplaceUnlessSemanticallyEqual is RequiresReplace for attributes of
+// a semantic equality type, so changing only the spelling of a value, like the
+// case of a domain, is an in-place update instead of a replacement.
+func requiresReplaceUnlessSemanticallyEqual(typ basetypes.StringTypable) planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			stateValue, diags := typ.ValueFromString(ctx, req.StateValue)
+			resp.Diagnostics.Append(diags...)
+			planValue, diags := typ.ValueFromString(ctx, req.PlanValue)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			semanticStateValue, ok := stateValue.(basetypes.StringValuableWithSemanticEquals)
+			if !ok {
+				resp.RequiresReplace = true
+				return
+			}
+
+			equal, diags := semanticStateValue.StringSemanticEquals(ctx, planValue)
+			resp.Diagnostics.Append(diags...)
+			resp.RequiresReplace = !equal
+		},
+		"If the value changes other than in spelling, Terraform will destroy and recreate the resource.",
+		"If the value changes other than in spelling, Terraform will destroy and recreate the resource.",
+	)
+}
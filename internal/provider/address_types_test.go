@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeDomainName(t *testing.T) {
+	cases := []struct {
+		name       string
+		normalized string
+		invalid    bool
+	}{
+		{name: "example.com", normalized: "example.com"},
+		{name: "Example.COM", normalized: "example.com"},
+		{name: "example.com.", normalized: "example.com"},
+		{name: "Bücher.Example", normalized: "xn--bcher-kva.example"},
+		{name: "xn--bcher-kva.example", normalized: "xn--bcher-kva.example"},
+		{name: "", invalid: true},
+		{name: "localhost", invalid: true},
+		{name: "example..com", invalid: true},
+		{name: "exa mple.com", invalid: true},
+		{name: "under_score.com", invalid: true},
+	}
+	for _, c := range cases {
+		normalized, err := normalizeDomainName(c.name)
+		if c.invalid {
+			if err == nil {
+				t.Errorf("normalizeDomainName(%q) = %q, want an error", c.name, normalized)
+			}
+			continue
+		}
+		if err != nil || normalized != c.normalized {
+			t.Errorf("normalizeDomainName(%q) = %q, %v, want %q", c.name, normalized, err, c.normalized)
+		}
+	}
+}
+
+func TestNormalizeEmailAddress(t *testing.T) {
+	cases := []struct {
+		address    string
+		normalized string
+		invalid    bool
+	}{
+		{address: "alice@example.com", normalized: "alice@example.com"},
+		{address: "Alice@Example.COM", normalized: "Alice@example.com"},
+		{address: "alice@bücher.example", normalized: "alice@xn--bcher-kva.example"},
+		{address: "alice", normalized: "alice"},
+		{address: "", invalid: true},
+		{address: "@example.com", invalid: true},
+		{address: "alice@", invalid: true},
+		{address: "alice@bob@example.com", invalid: true},
+		{address: "alice smith@example.com", invalid: true},
+	}
+	for _, c := range cases {
+		normalized, err := normalizeEmailAddress(c.address)
+		if c.invalid {
+			if err == nil {
+				t.Errorf("normalizeEmailAddress(%q) = %q, want an error", c.address, normalized)
+			}
+			continue
+		}
+		if err != nil || normalized != c.normalized {
+			t.Errorf("normalizeEmailAddress(%q) = %q, %v, want %q", c.address, normalized, err, c.normalized)
+		}
+	}
+}
+
+func TestNormalizeMailboxName(t *testing.T) {
+	cases := []struct {
+		name       string
+		normalized string
+		invalid    bool
+	}{
+		{name: "alice@example.com", normalized: "alice@example.com"},
+		{name: "Alice@Example.COM", normalized: "alice@example.com"},
+		{name: "Alice@Bücher.example", normalized: "alice@xn--bcher-kva.example"},
+		{name: "Alice", normalized: "alice"},
+		{name: "alice@", invalid: true},
+	}
+	for _, c := range cases {
+		normalized, err := normalizeMailboxName(c.name)
+		if c.invalid {
+			if err == nil {
+				t.Errorf("normalizeMailboxName(%q) = %q, want an error", c.name, normalized)
+			}
+			continue
+		}
+		if err != nil || normalized != c.normalized {
+			t.Errorf("normalizeMailboxName(%q) = %q, %v, want %q", c.name, normalized, err, c.normalized)
+		}
+	}
+
+	// User names that differ only in case are the same mailbox, other email
+	// addresses keep the case of their local part
+	if equal, _ := NewMailboxNameValue("Alice@Example.COM").StringSemanticEquals(context.Background(), NewMailboxNameValue("alice@example.com")); !equal {
+		t.Errorf("expected Alice@Example.COM and alice@example.com to be the same user name")
+	}
+	if semanticallyEqual(normalizeEmailAddress, "Alice@Example.COM", "alice@example.com") {
+		t.Errorf("expected the local part of email addresses to stay case sensitive")
+	}
+}
@@ -27,9 +27,9 @@ type AppPasswordEphemeralResource struct {
 
 // AppPasswordEphemeralResourceModel describes the ephemeral resource data model.
 type AppPasswordEphemeralResourceModel struct {
-	UserHandle  types.String `tfsdk:"user_handle"`
-	Name        types.String `tfsdk:"name"`
-	AppPassword types.String `tfsdk:"app_password"`
+	UserHandle  MailboxNameValue `tfsdk:"user_handle"`
+	Name        types.String     `tfsdk:"name"`
+	AppPassword types.String     `tfsdk:"app_password"`
 }
 
 func (e *AppPasswordEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
@@ -43,6 +43,7 @@ func (e *AppPasswordEphemeralResource) Schema(ctx context.Context, req ephemeral
 		Attributes: map[string]schema.Attribute{
 			"user_handle": schema.StringAttribute{
 				MarkdownDescription: "The user handle (email address or username) for which to create the app password.",
+				CustomType:          MailboxNameType{},
 				Required:            true,
 			},
 			"name": schema.StringAttribute{
@@ -2,14 +2,27 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
-
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -18,6 +31,14 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AppPasswordResource{}
+var _ resource.ResourceWithIdentity = &AppPasswordResource{}
+var _ resource.ResourceWithImportState = &AppPasswordResource{}
+var _ resource.ResourceWithModifyPlan = &AppPasswordResource{}
+var _ resource.ResourceWithConfigValidators = &AppPasswordResource{}
+
+// timeNow returns the current time. Tests replace it to move the clock forward
+// to when app passwords are due for rotation.
+var timeNow = time.Now
 
 func NewAppPasswordResource() resource.Resource {
 	return &AppPasswordResource{}
@@ -30,26 +51,81 @@ type AppPasswordResource struct {
 
 // AppPasswordResourceModel describes the resource data model.
 type AppPasswordResourceModel struct {
-	UserHandle  types.String `tfsdk:"user_handle"`
-	Name        types.String `tfsdk:"name"`
-	AppPassword types.String `tfsdk:"app_password"`
-	Id          types.String `tfsdk:"id"`
+	UserHandle           MailboxNameValue `tfsdk:"user_handle"`
+	Name                 types.String     `tfsdk:"name"`
+	RotationDays         types.Int64      `tfsdk:"rotation_days"`
+	Keepers              types.Map        `tfsdk:"keepers"`
+	PgpKey               types.String     `tfsdk:"pgp_key"`
+	AppPassword          types.String     `tfsdk:"app_password"`
+	EncryptedAppPassword types.String     `tfsdk:"encrypted_app_password"`
+	KeyFingerprint       types.String     `tfsdk:"key_fingerprint"`
+	Id                   types.String     `tfsdk:"id"`
+	CreatedAt            types.String     `tfsdk:"created_at"`
+	RotateAfter          types.String     `tfsdk:"rotate_after"`
+	Timeouts             timeouts.Value   `tfsdk:"timeouts"`
+}
+
+// AppPasswordResourceIdentityModel describes the resource identity. It holds
+// a hash of the app password instead of the secret itself.
+type AppPasswordResourceIdentityModel struct {
+	UserHandle        types.String `tfsdk:"user_handle"`
+	AppPasswordSha256 types.String `tfsdk:"app_password_sha256"`
+}
+
+// appPasswordIdentity returns the identity of an app password, with the domain
+// of the user handle normalized. Encrypted app passwords only have their hash
+// in state, as id.
+func appPasswordIdentity(data *AppPasswordResourceModel) AppPasswordResourceIdentityModel {
+	passwordSha256 := data.Id
+	if !data.AppPassword.IsNull() {
+		passwordSha256 = types.StringValue(appPasswordSha256(data.AppPassword.ValueString()))
+	}
+
+	return AppPasswordResourceIdentityModel{
+		UserHandle:        types.StringValue(normalizedOrRaw(normalizeMailboxName, data.UserHandle.ValueString())),
+		AppPasswordSha256: passwordSha256,
+	}
+}
+
+// appPasswordSha256 returns the hex-encoded SHA-256 hash of an app password.
+func appPasswordSha256(appPassword string) string {
+	sum := sha256.Sum256([]byte(appPassword))
+
+	return hex.EncodeToString(sum[:])
 }
 
 func (r *AppPasswordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_app_password"
 }
 
+func (r *AppPasswordResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"user_handle": identityschema.StringAttribute{
+				Description:       "The user handle the app password belongs to.",
+				RequiredForImport: true,
+			},
+			"app_password_sha256": identityschema.StringAttribute{
+				Description:       "The hex-encoded SHA-256 hash of the app password.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *AppPasswordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Manages a Purelymail app password. App passwords are alternative credentials for email clients.",
+		MarkdownDescription: "Manages a Purelymail app password. App passwords are alternative credentials for email clients. " +
+			"Set `rotation_days` or `keepers` to replace the password regularly or when other resources change, " +
+			"and `create_before_destroy` in the `lifecycle` block so the new password exists before the old one is deleted.",
 
 		Attributes: map[string]schema.Attribute{
 			"user_handle": schema.StringAttribute{
 				MarkdownDescription: "The user handle (email address or username) for which to create the app password.",
+				CustomType:          MailboxNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessSemanticallyEqual(MailboxNameType{}),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -57,25 +133,95 @@ func (r *AppPasswordResource) Schema(ctx context.Context, req resource.SchemaReq
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// Imported app passwords have no name in state yet
+							resp.RequiresReplace = !req.StateValue.IsNull()
+						},
+						"If the value changes, Terraform will destroy and recreate the resource, unless the app password was imported.",
+						"If the value changes, Terraform will destroy and recreate the resource, unless the app password was imported.",
+					),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"rotation_days": schema.Int64Attribute{
+				MarkdownDescription: "Number of days after which the app password is replaced with a new one, counted from `created_at`. The replacement shows up in the first plan after `rotate_after`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that replace the app password with a new one when they change, like the version of the client configuration that uses it. Adding keepers to an app password that had none, like an imported one, doesn't replace it.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = !req.StateValue.IsNull()
+						},
+						"If the value changes, Terraform will destroy and recreate the resource, unless it had no keepers before.",
+						"If the value changes, Terraform will destroy and recreate the resource, unless it had no keepers before.",
+					),
+				},
+			},
+			"pgp_key": schema.StringAttribute{
+				MarkdownDescription: "An ASCII-armored PGP public key, or a keybase.io user like `keybase:alice`, to encrypt the app password with. " +
+					"The password is then only stored encrypted, in `encrypted_app_password`, and `app_password` is null. " +
+					"The provider can't revoke encrypted app passwords, so destroying or replacing them only warns. Revoke them in the user's Purelymail settings. " +
+					"Conflicts with `rotation_days` and `keepers`, as every rotation would leave the previous password working.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					pgpKeyValidator{},
+				},
+			},
 			"app_password": schema.StringAttribute{
-				MarkdownDescription: "The generated app password (sensitive, only available after creation).",
+				MarkdownDescription: "The generated app password (sensitive, only available after creation). Null when `pgp_key` is set.",
 				Computed:            true,
 				Sensitive:           true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"encrypted_app_password": schema.StringAttribute{
+				MarkdownDescription: "The app password encrypted with `pgp_key`, as an ASCII-armored PGP message. Decrypt it with `gpg --decrypt`. Only set with `pgp_key`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_fingerprint": schema.StringAttribute{
+				MarkdownDescription: "The fingerprint of the PGP key that `encrypted_app_password` is encrypted with. Only set with `pgp_key`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"id": schema.StringAttribute{
-				MarkdownDescription: "The app password identifier (same as app_password).",
+				MarkdownDescription: "The app password identifier (same as app_password, or its hex-encoded SHA-256 hash when `pgp_key` is set).",
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "When the app password was created, as an RFC 3339 timestamp. For imported app passwords, when they were imported.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"rotate_after": schema.StringAttribute{
+				MarkdownDescription: "When the app password is due for rotation, as an RFC 3339 timestamp. Only set with `rotation_days`.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			// Reads and updates make no API calls
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Delete: true}),
 		},
 	}
 }
@@ -105,6 +251,26 @@ func (r *AppPasswordResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	// Resolve the key first, so no app password is created that can't be
+	// encrypted
+	var pgpEntity *openpgp.Entity
+	if !data.PgpKey.IsNull() {
+		setStep(ctx, "resolve PGP key")
+		var err error
+		pgpEntity, err = resolvePGPKey(ctx, data.PgpKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "Invalid PGP Key", err.Error())
+			return
+		}
+	}
+
 	// Create app password via API
 	createReq := api.CreateAppPassword{
 		UserHandle: data.UserHandle.ValueString(),
@@ -115,6 +281,7 @@ func (r *AppPasswordResource) Create(ctx context.Context, req resource.CreateReq
 		createReq.Name = &name
 	}
 
+	setStep(ctx, "create app password")
 	httpResp, err := r.client.CreateAppPassword(ctx, createReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create app password: %s", err))
@@ -139,17 +306,39 @@ func (r *AppPasswordResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	// Set the app password and ID
-	data.AppPassword = types.StringValue(*createResp.Result.AppPassword)
-	data.Id = types.StringValue(*createResp.Result.AppPassword)
+	appPassword := *createResp.Result.AppPassword
+	data.AppPassword = types.StringValue(appPassword)
+	data.Id = types.StringValue(appPassword)
+	data.EncryptedAppPassword = types.StringNull()
+	data.KeyFingerprint = types.StringNull()
+
+	// Only keep the encrypted password, and its hash to identify it
+	if pgpEntity != nil {
+		encrypted, err := encryptWithPGPKey(pgpEntity, appPassword)
+		if err != nil {
+			resp.Diagnostics.AddError("Encryption Error", fmt.Sprintf("Unable to encrypt app password: %s", err))
+			r.revokeAppPassword(ctx, data.UserHandle.ValueString(), appPassword, &resp.Diagnostics)
+			return
+		}
+
+		data.AppPassword = types.StringNull()
+		data.Id = types.StringValue(appPasswordSha256(appPassword))
+		data.EncryptedAppPassword = types.StringValue(encrypted)
+		data.KeyFingerprint = types.StringValue(pgpKeyFingerprint(pgpEntity))
+	}
 
 	// Set default name if not provided
-	if data.Name.IsNull() {
+	if data.Name.IsNull() || data.Name.IsUnknown() {
 		data.Name = types.StringValue("")
 	}
 
+	data.CreatedAt = types.StringValue(timeNow().UTC().Format(time.RFC3339))
+	data.RotateAfter = appPasswordRotateAfter(data.CreatedAt, data.RotationDays)
+
 	tflog.Trace(ctx, "created purelymail_app_password resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, appPasswordIdentity(&data))...)
 }
 
 func (r *AppPasswordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -162,14 +351,50 @@ func (r *AppPasswordResource) Read(ctx context.Context, req resource.ReadRequest
 
 	// App passwords cannot be read back from the API - they're write-only
 	// We just keep what's in state
+
+	// Imported app passwords and ones created before rotation was supported
+	// have no creation time, so their rotation is counted from now
+	if data.CreatedAt.IsNull() {
+		data.CreatedAt = types.StringValue(timeNow().UTC().Format(time.RFC3339))
+	}
+	data.RotateAfter = appPasswordRotateAfter(data.CreatedAt, data.RotationDays)
+
 	tflog.Trace(ctx, "read purelymail_app_password resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, appPasswordIdentity(&data))...)
 }
 
 func (r *AppPasswordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// App passwords don't support updates - all fields require replacement
-	resp.Diagnostics.AddError("Update Not Supported", "App passwords cannot be updated. All changes require replacement.")
+	var data AppPasswordResourceModel
+	var state AppPasswordResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// App passwords don't support updates. Only the spelling of user_handle,
+	// rotation_days, and the name and keepers of imported app passwords change
+	// in place, so there is nothing to send.
+	keepPriorSpelling(&data.UserHandle, state.UserHandle)
+	data.AppPassword = state.AppPassword
+	data.EncryptedAppPassword = state.EncryptedAppPassword
+	data.KeyFingerprint = state.KeyFingerprint
+	data.Id = state.Id
+	data.CreatedAt = state.CreatedAt
+	data.RotateAfter = appPasswordRotateAfter(data.CreatedAt, data.RotationDays)
+
+	// Imported app passwords without a configured name
+	if data.Name.IsUnknown() {
+		data.Name = types.StringValue("")
+	}
+
+	tflog.Trace(ctx, "updated purelymail_app_password resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, appPasswordIdentity(&data))...)
 }
 
 func (r *AppPasswordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -180,23 +405,168 @@ func (r *AppPasswordResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	// Delete app password via API
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	// The API needs the password itself, which isn't in state when encrypted
+	if data.AppPassword.IsNull() {
+		resp.Diagnostics.AddWarning(
+			"App Password Not Revoked",
+			fmt.Sprintf("The app password of %s with key fingerprint %s was encrypted with pgp_key, so the provider can't revoke it. "+
+				"It was removed from state but still works until it is revoked in the user's Purelymail settings.",
+				data.UserHandle.ValueString(), data.KeyFingerprint.ValueString()),
+		)
+		return
+	}
+
+	r.revokeAppPassword(ctx, data.UserHandle.ValueString(), data.AppPassword.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "deleted purelymail_app_password resource")
+}
+
+// revokeAppPassword deletes an app password via API.
+func (r *AppPasswordResource) revokeAppPassword(ctx context.Context, userHandle string, appPassword string, diags *diag.Diagnostics) {
 	deleteReq := api.DeleteAppPasswordRequest{
-		UserName:    data.UserHandle.ValueString(),
-		AppPassword: data.AppPassword.ValueString(),
+		UserName:    userHandle,
+		AppPassword: appPassword,
 	}
 
+	setStep(ctx, "delete app password")
 	httpResp, err := r.client.DeleteAppPassword(ctx, deleteReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete app password: %s", err))
+		diags.AddError("Client Error", fmt.Sprintf("Unable to delete app password: %s", err))
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete app password (status: %d)", httpResp.StatusCode))
+		diags.AddError("API Error", fmt.Sprintf("Failed to delete app password (status: %d)", httpResp.StatusCode))
+	}
+}
+
+func (r *AppPasswordResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	// Encrypted app passwords can't be revoked, so rotating them would leave
+	// every previous password working
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("pgp_key"),
+			path.MatchRoot("rotation_days"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("pgp_key"),
+			path.MatchRoot("keepers"),
+		),
+	}
+}
+
+func (r *AppPasswordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on create
+	if req.State.Raw.IsNull() {
 		return
 	}
 
-	tflog.Trace(ctx, "deleted purelymail_app_password resource")
+	var state AppPasswordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		warnAppPasswordNotRevoked(&state, "destroyed", resp)
+		return
+	}
+
+	var plan AppPasswordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The attributes that replace the app password when they change
+	if !plan.UserHandle.IsUnknown() && !semanticallyEqual(normalizeMailboxName, plan.UserHandle.ValueString(), state.UserHandle.ValueString()) ||
+		!state.Name.IsNull() && !plan.Name.Equal(state.Name) ||
+		!plan.PgpKey.Equal(state.PgpKey) {
+		warnAppPasswordNotRevoked(&state, "replaced", resp)
+	}
+
+	// rotation_days may have changed, so rotate_after is recomputed from the
+	// creation time instead of taken from state
+	rotateAfter := appPasswordRotateAfter(state.CreatedAt, plan.RotationDays)
+	if !rotateAfter.IsNull() && !rotateAfter.IsUnknown() {
+		due, err := time.Parse(time.RFC3339, rotateAfter.ValueString())
+		if err == nil && !timeNow().Before(due) {
+			// Terraform only replaces resources for attributes that change
+			rotateAfter = types.StringUnknown()
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("rotate_after"))
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("rotate_after"), rotateAfter)...)
+}
+
+func (r *AppPasswordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The identity only holds a hash, which isn't enough to delete the app
+	// password later
+	if req.ID == "" {
+		resp.Diagnostics.AddError(
+			"Import by Identity Not Supported",
+			"App passwords can't be imported by identity, as it only holds a hash of the password. Import them with an ID of 'user_handle/app_password' instead.",
+		)
+		return
+	}
+
+	// Import format: "user_handle/app_password". User handles can't contain
+	// a slash, so the app password is everything after the first one.
+	userHandle, appPassword, ok := strings.Cut(req.ID, "/")
+	if !ok || userHandle == "" || appPassword == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"Expected import ID in format 'user_handle/app_password'.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_handle"), userHandle)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_password"), appPassword)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), appPassword)...)
+}
+
+// warnAppPasswordNotRevoked warns that an encrypted app password that is
+// about to be destroyed or replaced can't be revoked, so it keeps working.
+func warnAppPasswordNotRevoked(state *AppPasswordResourceModel, action string, resp *resource.ModifyPlanResponse) {
+	if !state.AppPassword.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"App Password Will Not Be Revoked",
+		fmt.Sprintf("The app password of %s with key fingerprint %s is encrypted with pgp_key, so the provider can't revoke it when it is %s. "+
+			"It will keep working until it is revoked in the user's Purelymail settings.",
+			state.UserHandle.ValueString(), state.KeyFingerprint.ValueString(), action),
+	)
+}
+
+// appPasswordRotateAfter returns when an app password created at createdAt is
+// due for rotation, which is null without rotation_days.
+func appPasswordRotateAfter(createdAt types.String, rotationDays types.Int64) types.String {
+	if rotationDays.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if createdAt.IsNull() || createdAt.IsUnknown() || rotationDays.IsNull() {
+		return types.StringNull()
+	}
+
+	created, err := time.Parse(time.RFC3339, createdAt.ValueString())
+	if err != nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(created.AddDate(0, 0, int(rotationDays.ValueInt64())).Format(time.RFC3339))
 }
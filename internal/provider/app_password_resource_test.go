@@ -1,14 +1,26 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
 )
@@ -47,6 +59,34 @@ func TestAccAppPasswordResource(t *testing.T) {
 	})
 }
 
+func TestAccAppPasswordResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppPasswordResourceConfig(ts.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_app_password.test", map[string]knownvalue.Check{
+						"user_handle":         knownvalue.StringExact("alice@example.com"),
+						"app_password_sha256": knownvalue.StringRegexp(regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					}),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
 func testAccAppPasswordResourceConfig(endpoint string) string {
 	return `
 provider "purelymail" {
@@ -60,3 +100,317 @@ resource "purelymail_app_password" "test" {
 }
 `
 }
+
+func TestAccAppPasswordResourceRotation(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Move the clock past rotate_after instead of waiting for it
+	t.Cleanup(func() { timeNow = time.Now })
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppPasswordResourceConfigRotation(ts.URL, 30, "v1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("app_password"), knownvalue.StringExact("app-password-1")),
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("rotate_after"), knownvalue.StringFunc(func(value string) error {
+						rotateAfter, err := time.Parse(time.RFC3339, value)
+						if err != nil {
+							return err
+						}
+						if days := time.Until(rotateAfter).Hours() / 24; days < 29 || days > 30 {
+							return fmt.Errorf("expected rotation in 30 days, got %.1f days", days)
+						}
+						return nil
+					})),
+				},
+			},
+			// Not due yet
+			{
+				PreConfig: func() {
+					timeNow = func() time.Time { return time.Now().Add(29 * 24 * time.Hour) }
+				},
+				Config: testAccAppPasswordResourceConfigRotation(ts.URL, 30, "v1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_app_password.test", plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			// Due, the new password is created before the old one is deleted
+			{
+				PreConfig: func() {
+					timeNow = func() time.Time { return time.Now().Add(31 * 24 * time.Hour) }
+				},
+				Config: testAccAppPasswordResourceConfigRotation(ts.URL, 30, "v1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_app_password.test", plancheck.ResourceActionCreateBeforeDestroy),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("app_password"), knownvalue.StringExact("app-password-2")),
+				},
+			},
+			// Changing a keeper rotates as well
+			{
+				PreConfig: func() {
+					if mockServer.HasAppPassword("app-password-1") {
+						t.Fatal("expected the rotated app password to be deleted")
+					}
+				},
+				Config: testAccAppPasswordResourceConfigRotation(ts.URL, 30, "v2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_app_password.test", plancheck.ResourceActionCreateBeforeDestroy),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("app_password"), knownvalue.StringExact("app-password-3")),
+				},
+			},
+			// Changing rotation_days only moves rotate_after
+			{
+				Config: testAccAppPasswordResourceConfigRotation(ts.URL, 90, "v2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_app_password.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("rotate_after"), knownvalue.NotNull()),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("app_password"), knownvalue.StringExact("app-password-3")),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccAppPasswordResourceConfigRotation(endpoint string, rotationDays int, version string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_app_password" "test" {
+  user_handle   = "alice@example.com"
+  name          = "imap"
+  rotation_days = %[2]d
+
+  keepers = {
+    client_config = %[3]q
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`, endpoint, rotationDays, version)
+}
+
+func TestAccAppPasswordResourceImport(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	mockServer.AddAppPassword("alice@example.com", "existing/app-password")
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAppPasswordResourceConfigImport(ts.URL, "alice@example.com"),
+				ExpectError: regexp.MustCompile(`Expected\s+import\s+ID\s+in\s+format\s+'user_handle/app_password'`),
+			},
+			// The name and keepers are taken over without replacing the password
+			{
+				Config: testAccAppPasswordResourceConfigImport(ts.URL, "alice@example.com/existing/app-password"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_app_password.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("app_password"), knownvalue.StringExact("existing/app-password")),
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("name"), knownvalue.StringExact("imap")),
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("rotate_after"), knownvalue.NotNull()),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if mockServer.HasAppPassword("existing/app-password") {
+				return fmt.Errorf("expected the imported app password to be deleted")
+			}
+			return nil
+		},
+	})
+}
+
+func testAccAppPasswordResourceConfigImport(endpoint string, importId string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+import {
+  to = purelymail_app_password.test
+  id = %[2]q
+}
+
+resource "purelymail_app_password" "test" {
+  user_handle   = "alice@example.com"
+  name          = "imap"
+  rotation_days = 30
+
+  keepers = {
+    client_config = "v1"
+  }
+}
+`, endpoint, importId)
+}
+
+func TestAccAppPasswordResourcePgpKey(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	entity, armoredKey := testPGPKey(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAppPasswordResourceConfigPgpKey(ts.URL, "not a key", ""),
+				ExpectError: regexp.MustCompile(`Invalid\s+PGP\s+Key`),
+			},
+			// Rotating encrypted passwords would leave the old ones working
+			{
+				Config:      testAccAppPasswordResourceConfigPgpKey(ts.URL, armoredKey, "rotation_days = 30"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config:      testAccAppPasswordResourceConfigPgpKey(ts.URL, armoredKey, `keepers = { version = "1" }`),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			// Only the encrypted password and its hash are in state
+			{
+				Config: testAccAppPasswordResourceConfigPgpKey(ts.URL, armoredKey, ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("app_password"), knownvalue.Null()),
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("id"), knownvalue.StringExact(appPasswordSha256("app-password-1"))),
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("key_fingerprint"), knownvalue.StringExact(pgpKeyFingerprint(entity))),
+					statecheck.ExpectKnownValue("purelymail_app_password.test", tfjsonpath.New("encrypted_app_password"), knownvalue.StringFunc(func(value string) error {
+						plaintext, err := testPGPDecrypt(entity, value)
+						if err != nil {
+							return err
+						}
+						if plaintext != "app-password-1" {
+							return fmt.Errorf("expected app-password-1, got %q", plaintext)
+						}
+						return nil
+					})),
+					statecheck.ExpectIdentity("purelymail_app_password.test", map[string]knownvalue.Check{
+						"user_handle":         knownvalue.StringExact("alice@example.com"),
+						"app_password_sha256": knownvalue.StringExact(appPasswordSha256("app-password-1")),
+					}),
+				},
+			},
+			// Delete only warns, the password can't be revoked without it
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if !mockServer.HasAppPassword("app-password-1") {
+				return fmt.Errorf("expected the encrypted app password to be left alone")
+			}
+			return nil
+		},
+	})
+}
+
+func testAccAppPasswordResourceConfigPgpKey(endpoint string, pgpKey string, attributes string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_app_password" "test" {
+  user_handle = "alice@example.com"
+  pgp_key     = %[2]q
+  %[3]s
+}
+`, endpoint, pgpKey, attributes)
+}
+
+func TestAppPasswordResourceModifyPlanNotRevoked(t *testing.T) {
+	ctx := context.Background()
+	r := &AppPasswordResource{}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	value := func(json string) tftypes.Value {
+		v, err := (&tfprotov6.DynamicValue{JSON: []byte(json)}).Unmarshal(schemaType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	encrypted := `{"user_handle":"alice@example.com","name":"imap","pgp_key":"key-1","app_password":null,"encrypted_app_password":"-----BEGIN PGP MESSAGE-----","key_fingerprint":"ABCD","id":"hash","created_at":"2026-01-01T00:00:00Z"}`
+	plaintext := `{"user_handle":"alice@example.com","name":"imap","pgp_key":null,"app_password":"secret","id":"secret","created_at":"2026-01-01T00:00:00Z"}`
+
+	rekeyed := strings.Replace(encrypted, "key-1", "key-2", 1)
+
+	cases := []struct {
+		name  string
+		state string
+		plan  string
+		warn  bool
+	}{
+		{name: "destroy encrypted", state: encrypted, warn: true},
+		{name: "destroy plaintext", state: plaintext, warn: false},
+		{name: "replace encrypted", state: encrypted, plan: rekeyed, warn: true},
+		{name: "keep encrypted", state: encrypted, plan: encrypted, warn: false},
+	}
+
+	for _, c := range cases {
+		// No plan is a destroy
+		plan := tftypes.NewValue(schemaType, nil)
+		if c.plan != "" {
+			plan = value(c.plan)
+		}
+
+		req := fwresource.ModifyPlanRequest{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: value(c.state)},
+			Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", c.name, resp.Diagnostics)
+		}
+
+		if got := resp.Diagnostics.WarningsCount() > 0; got != c.warn {
+			t.Errorf("%s: got warning %t, want %t", c.name, got, c.warn)
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CatchallResource{}
+var _ resource.ResourceWithImportState = &CatchallResource{}
+var _ resource.ResourceWithIdentity = &CatchallResource{}
+var _ resource.ResourceWithModifyPlan = &CatchallResource{}
+
+func NewCatchallResource() resource.Resource {
+	return &CatchallResource{}
+}
+
+// CatchallResource defines the resource implementation.
+type CatchallResource struct {
+	client *api.Client
+}
+
+// CatchallResourceModel describes the resource data model.
+type CatchallResourceModel struct {
+	Id              types.Int64     `tfsdk:"id"`
+	DomainName      DomainNameValue `tfsdk:"domain_name"`
+	TargetAddresses types.List      `tfsdk:"target_addresses"`
+	AdoptExisting   types.Bool      `tfsdk:"adopt_existing"`
+	Timeouts        timeouts.Value  `tfsdk:"timeouts"`
+}
+
+// CatchallResourceIdentityModel describes the resource identity.
+type CatchallResourceIdentityModel struct {
+	DomainName types.String `tfsdk:"domain_name"`
+}
+
+// catchallIdentity returns the identity of a catch-all rule, with the domain
+// name normalized.
+func catchallIdentity(data *CatchallResourceModel) CatchallResourceIdentityModel {
+	return CatchallResourceIdentityModel{
+		DomainName: types.StringValue(normalizedOrRaw(normalizeDomainName, data.DomainName.ValueString())),
+	}
+}
+
+func (r *CatchallResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_catchall"
+}
+
+func (r *CatchallResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain_name": identityschema.StringAttribute{
+				Description:       "The domain name of the catch-all rule.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+func (r *CatchallResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the catch-all routing rule of a Purelymail domain. A domain can have at most one catch-all, which receives mail for any address that does not match an existing user.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the underlying routing rule.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain_name": schema.StringAttribute{
+				MarkdownDescription: "The domain name this catch-all applies to.",
+				CustomType:          DomainNameType{},
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessSemanticallyEqual(DomainNameType{}),
+				},
+			},
+			"target_addresses": schema.ListAttribute{
+				MarkdownDescription: "List of email addresses that receive mail for unmatched addresses on the domain.",
+				Required:            true,
+				ElementType:         EmailAddressType{},
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to take over a catch-all that already exists on the domain instead of failing. The existing rule is replaced if its targets differ.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+func (r *CatchallResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *CatchallResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CatchallResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	var targetAddresses []string
+	resp.Diagnostics.Append(data.TargetAddresses.ElementsAs(ctx, &targetAddresses, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A domain can only have one catch-all, so look for an existing one first
+	rules, err := listRoutingRules(ctx, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to list routing rules: %s", err))
+		return
+	}
+
+	existing := findCatchall(rules, data.DomainName.ValueString())
+	if existing != nil {
+		if !data.AdoptExisting.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("domain_name"),
+				"Catch-all Already Exists",
+				fmt.Sprintf("Domain %s already has a catch-all routing rule (ID %d). Import it with the domain name, or set adopt_existing = true to take it over.",
+					data.DomainName.ValueString(), *existing.Id),
+			)
+			return
+		}
+
+		if existing.TargetAddresses != nil && slices.Equal(*existing.TargetAddresses, targetAddresses) {
+			data.Id = types.Int64Value(int64(*existing.Id))
+		} else {
+			// Routing rules can't be updated in place, so replace the adopted rule
+			if err := r.deleteCatchall(ctx, *existing.Id); err != nil {
+				resp.Diagnostics.AddError("Adopt Error", fmt.Sprintf("Unable to replace existing catch-all: %s", err))
+				return
+			}
+			if err := r.createCatchall(ctx, data.DomainName.ValueString(), targetAddresses); err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create catch-all: %s", err))
+				return
+			}
+			data.Id = types.Int64Unknown()
+		}
+
+		tflog.Debug(ctx, "adopted existing catch-all", map[string]interface{}{
+			"domain":  data.DomainName.ValueString(),
+			"rule_id": *existing.Id,
+		})
+	} else {
+		if err := r.createCatchall(ctx, data.DomainName.ValueString(), targetAddresses); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create catch-all: %s", err))
+			return
+		}
+		data.Id = types.Int64Unknown()
+	}
+
+	found, err := r.readCatchall(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read catch-all after creation: %s", err))
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Catch-all for domain %s not found after creation", data.DomainName.ValueString()))
+		return
+	}
+
+	tflog.Trace(ctx, "created purelymail_catchall resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, catchallIdentity(&data))...)
+}
+
+func (r *CatchallResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CatchallResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "read", data.Timeouts.Read, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	found, err := r.readCatchall(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read catch-all: %s", err))
+		return
+	}
+
+	if !found {
+		// Catch-all was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Imported resources have no adopt_existing value yet
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+
+	tflog.Trace(ctx, "read purelymail_catchall resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, catchallIdentity(&data))...)
+}
+
+func (r *CatchallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data CatchallResourceModel
+	var state CatchallResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "update", data.Timeouts.Update, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	keepPriorSpelling(&data.DomainName, state.DomainName)
+
+	var targetAddresses []string
+	resp.Diagnostics.Append(data.TargetAddresses.ElementsAs(ctx, &targetAddresses, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var stateTargetAddresses []string
+	resp.Diagnostics.Append(state.TargetAddresses.ElementsAs(ctx, &stateTargetAddresses, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only adopt_existing changed, nothing to send to the API
+	if slices.Equal(targetAddresses, stateTargetAddresses) {
+		data.Id = state.Id
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, catchallIdentity(&data))...)
+		return
+	}
+
+	// Routing rules don't have an update API, so we need to delete and recreate
+	if err := r.deleteCatchall(ctx, int32(state.Id.ValueInt64())); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete catch-all during update: %s", err))
+		return
+	}
+
+	if err := r.createCatchall(ctx, data.DomainName.ValueString(), targetAddresses); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create catch-all during update: %s", err))
+		return
+	}
+
+	// Clear the old ID so readCatchall will search by domain
+	data.Id = types.Int64Unknown()
+
+	found, err := r.readCatchall(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read catch-all after update: %s", err))
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Catch-all for domain %s not found after update", data.DomainName.ValueString()))
+		return
+	}
+
+	tflog.Trace(ctx, "updated purelymail_catchall resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, catchallIdentity(&data))...)
+}
+
+func (r *CatchallResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CatchallResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	if err := r.deleteCatchall(ctx, int32(data.Id.ValueInt64())); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete catch-all: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "deleted purelymail_catchall resource")
+}
+
+func (r *CatchallResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state CatchallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Changing the targets recreates the underlying rule, which gets a new ID
+	if !plan.TargetAddresses.Equal(state.TargetAddresses) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.Int64Unknown())...)
+	}
+}
+
+func (r *CatchallResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by domain name, the rule ID is looked up on read
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("domain_name"), path.Root("domain_name"), req, resp)
+}
+
+// createCatchall creates a catch-all routing rule for the domain. Purelymail
+// represents a catch-all as an empty prefix match that only applies to
+// addresses without a matching user.
+func (r *CatchallResource) createCatchall(ctx context.Context, domainName string, targetAddresses []string) error {
+	setStep(ctx, "create catch-all")
+	catchall := true
+	httpResp, err := r.client.CreateRoutingRule(ctx, api.CreateRoutingRequest{
+		DomainName:      domainName,
+		Prefix:          true,
+		MatchUser:       "",
+		TargetAddresses: targetAddresses,
+		Catchall:        &catchall,
+	})
+	if err != nil {
+		return fmt.Errorf("client error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", httpResp.StatusCode)
+	}
+
+	return nil
+}
+
+// deleteCatchall deletes the routing rule with the given ID.
+func (r *CatchallResource) deleteCatchall(ctx context.Context, id int32) error {
+	setStep(ctx, "delete catch-all")
+	httpResp, err := r.client.DeleteRoutingRule(ctx, api.DeleteRoutingRequest{
+		RoutingRuleId: id,
+	})
+	if err != nil {
+		return fmt.Errorf("client error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", httpResp.StatusCode)
+	}
+
+	return nil
+}
+
+// readCatchall reads the catch-all rule from the API and updates the model.
+// The rule is looked up by ID when known, otherwise by domain name.
+// Returns (found bool, error).
+func (r *CatchallResource) readCatchall(ctx context.Context, data *CatchallResourceModel) (bool, error) {
+	setStep(ctx, "read catch-all")
+	rules, err := listRoutingRules(ctx, r.client)
+	if err != nil {
+		return false, err
+	}
+
+	var foundRule *api.RoutingRule
+	if !data.Id.IsNull() && !data.Id.IsUnknown() {
+		targetId := int32(data.Id.ValueInt64())
+		for _, rule := range rules {
+			if rule.Id != nil && *rule.Id == targetId {
+				ruleCopy := rule
+				foundRule = &ruleCopy
+				break
+			}
+		}
+	} else {
+		foundRule = findCatchall(rules, data.DomainName.ValueString())
+	}
+
+	if foundRule == nil {
+		return false, nil
+	}
+
+	if foundRule.Id != nil {
+		data.Id = types.Int64Value(int64(*foundRule.Id))
+	}
+	if foundRule.DomainName != nil {
+		data.DomainName = NewDomainNameValue(*foundRule.DomainName)
+	}
+	if foundRule.TargetAddresses != nil {
+		targetList, diags := types.ListValueFrom(ctx, EmailAddressType{}, *foundRule.TargetAddresses)
+		if diags.HasError() {
+			return false, fmt.Errorf("unable to convert target addresses")
+		}
+		data.TargetAddresses = targetList
+	}
+
+	return true, nil
+}
+
+// findCatchall returns the catch-all rule of the domain, or nil if it has none.
+func findCatchall(rules []api.RoutingRule, domainName string) *api.RoutingRule {
+	for _, rule := range rules {
+		if rule.DomainName != nil && sameDomainName(*rule.DomainName, domainName) &&
+			rule.Catchall != nil && *rule.Catchall {
+			ruleCopy := rule
+			return &ruleCopy
+		}
+	}
+	return nil
+}
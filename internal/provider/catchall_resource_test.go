@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestAccCatchallResource(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccCatchallResourceConfig(ts.URL, `["postmaster@example.com"]`, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_catchall.test", tfjsonpath.New("domain_name"), knownvalue.StringExact("example.com")),
+					statecheck.ExpectKnownValue("purelymail_catchall.test", tfjsonpath.New("target_addresses"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("postmaster@example.com"),
+					})),
+					statecheck.ExpectKnownValue("purelymail_catchall.test", tfjsonpath.New("id"), knownvalue.Int64Exact(1)),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "purelymail_catchall.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "example.com",
+			},
+			// Update and Read testing
+			{
+				Config: testAccCatchallResourceConfig(ts.URL, `["postmaster@example.com", "admin@example.com"]`, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_catchall.test", tfjsonpath.New("target_addresses"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("postmaster@example.com"),
+						knownvalue.StringExact("admin@example.com"),
+					})),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func TestAccCatchallResourceExisting(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Seed a catch-all that was created outside of Terraform
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catchall := true
+	httpResp, err := client.CreateRoutingRule(context.Background(), api.CreateRoutingRequest{
+		DomainName:      "example.com",
+		Prefix:          true,
+		MatchUser:       "",
+		TargetAddresses: []string{"legacy@example.com"},
+		Catchall:        &catchall,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A second catch-all on the same domain is refused
+			{
+				Config:      testAccCatchallResourceConfig(ts.URL, `["postmaster@example.com"]`, false),
+				ExpectError: regexp.MustCompile("Catch-all Already Exists"),
+			},
+			// Adopting the existing catch-all replaces its targets
+			{
+				Config: testAccCatchallResourceConfig(ts.URL, `["postmaster@example.com"]`, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_catchall.test", tfjsonpath.New("target_addresses"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("postmaster@example.com"),
+					})),
+					statecheck.ExpectKnownValue("purelymail_catchall.test", tfjsonpath.New("id"), knownvalue.Int64Exact(2)),
+				},
+			},
+		},
+	})
+}
+
+func TestAccCatchallResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCatchallResourceConfig(ts.URL, `["postmaster@example.com"]`, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_catchall.test", map[string]knownvalue.Check{
+						"domain_name": knownvalue.StringExact("example.com"),
+					}),
+				},
+			},
+			{
+				ResourceName:    "purelymail_catchall.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccCatchallResourceConfig(endpoint string, targetAddresses string, adoptExisting bool) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_catchall" "test" {
+  domain_name      = "example.com"
+  target_addresses = %[2]s
+  adopt_existing   = %[3]t
+}
+`, endpoint, targetAddresses, adoptExisting)
+}
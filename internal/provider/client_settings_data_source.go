@@ -0,0 +1,475 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ClientSettingsDataSource{}
+
+func NewClientSettingsDataSource() datasource.DataSource {
+	return &ClientSettingsDataSource{}
+}
+
+// ClientSettingsDataSource implements the purelymail_client_settings data
+// source. The settings are the same for every user, so it makes no API calls.
+type ClientSettingsDataSource struct{}
+
+// ClientSettingsDataSourceModel is the state model.
+type ClientSettingsDataSourceModel struct {
+	UserName           MailboxNameValue `tfsdk:"user_name"`
+	DisplayName        types.String     `tfsdk:"display_name"`
+	RenderAutoconfig   types.Bool       `tfsdk:"render_autoconfig"`
+	RenderMobileconfig types.Bool       `tfsdk:"render_mobileconfig"`
+	Username           types.String     `tfsdk:"username"`
+	Imap               types.Object     `tfsdk:"imap"`
+	Smtp               types.Object     `tfsdk:"smtp"`
+	Pop3               types.Object     `tfsdk:"pop3"`
+	CaldavUrl          types.String     `tfsdk:"caldav_url"`
+	CarddavUrl         types.String     `tfsdk:"carddav_url"`
+	WebmailUrl         types.String     `tfsdk:"webmail_url"`
+	AutoconfigXml      types.String     `tfsdk:"autoconfig_xml"`
+	Mobileconfig       types.String     `tfsdk:"mobileconfig"`
+	Id                 types.String     `tfsdk:"id"`
+}
+
+// ClientServerModel describes a mail server that clients connect to.
+type ClientServerModel struct {
+	Hostname types.String `tfsdk:"hostname"`
+	Port     types.Int64  `tfsdk:"port"`
+	Security types.String `tfsdk:"security"`
+}
+
+// clientServerAttrTypes are the attribute types of ClientServerModel.
+var clientServerAttrTypes = map[string]attr.Type{
+	"hostname": types.StringType,
+	"port":     types.Int64Type,
+	"security": types.StringType,
+}
+
+// clientServer is a mail server of Purelymail. Security is "ssl" for implicit
+// TLS.
+type clientServer struct {
+	Hostname string
+	Port     int64
+	Security string
+}
+
+// Purelymail's client endpoints, see https://purelymail.com/docs/clientSettings.
+var (
+	purelymailImap = clientServer{Hostname: "imap.purelymail.com", Port: 993, Security: "ssl"}
+	purelymailSmtp = clientServer{Hostname: "smtp.purelymail.com", Port: 465, Security: "ssl"}
+	purelymailPop3 = clientServer{Hostname: "pop.purelymail.com", Port: 995, Security: "ssl"}
+	purelymailDav  = clientServer{Hostname: purelymailDavHostname, Port: 443, Security: "ssl"}
+)
+
+const (
+	purelymailDavHostname = "purelymail.com"
+	purelymailDavPath     = "/webdav/"
+	purelymailDavUrl      = "https://" + purelymailDavHostname + purelymailDavPath
+	purelymailWebmailUrl  = "https://inbox.purelymail.com/"
+)
+
+func (d *ClientSettingsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_client_settings"
+}
+
+func (d *ClientSettingsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	serverAttributes := func(protocol string) map[string]schema.Attribute {
+		return map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The hostname of the %s server.", protocol),
+				Computed:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The port of the %s server.", protocol),
+				Computed:            true,
+			},
+			"security": schema.StringAttribute{
+				MarkdownDescription: "The connection security, `ssl` for TLS from the start of the connection.",
+				Computed:            true,
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns the settings that email, calendar and contacts clients need to connect to a Purelymail mailbox, " +
+			"optionally rendered as a Thunderbird autoconfig file and an Apple configuration profile. " +
+			"The settings are the same for every user, so the data source makes no API calls and can be read before the user exists.",
+		Attributes: map[string]schema.Attribute{
+			"user_name": schema.StringAttribute{
+				MarkdownDescription: "The email address of the user.",
+				CustomType:          MailboxNameType{},
+				Required:            true,
+			},
+			"display_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the account in the Apple configuration profile, like the user's full name. Defaults to `user_name`.",
+				Optional:            true,
+			},
+			"render_autoconfig": schema.BoolAttribute{
+				MarkdownDescription: "Whether to render `autoconfig_xml`. Requires `user_name` to have a domain.",
+				Optional:            true,
+			},
+			"render_mobileconfig": schema.BoolAttribute{
+				MarkdownDescription: "Whether to render `mobileconfig`.",
+				Optional:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The username to log in with, which is the full email address for every protocol.",
+				Computed:            true,
+			},
+			"imap": schema.SingleNestedAttribute{
+				MarkdownDescription: "The IMAP server for receiving mail.",
+				Computed:            true,
+				Attributes:          serverAttributes("IMAP"),
+			},
+			"smtp": schema.SingleNestedAttribute{
+				MarkdownDescription: "The SMTP submission server for sending mail.",
+				Computed:            true,
+				Attributes:          serverAttributes("SMTP"),
+			},
+			"pop3": schema.SingleNestedAttribute{
+				MarkdownDescription: "The POP3 server for receiving mail.",
+				Computed:            true,
+				Attributes:          serverAttributes("POP3"),
+			},
+			"caldav_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the CalDAV server for calendars.",
+				Computed:            true,
+			},
+			"carddav_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the CardDAV server for contacts.",
+				Computed:            true,
+			},
+			"webmail_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the webmail interface.",
+				Computed:            true,
+			},
+			"autoconfig_xml": schema.StringAttribute{
+				MarkdownDescription: "A Thunderbird autoconfig file for the domain of `user_name`, with `render_autoconfig`. " +
+					"Serve it at `https://autoconfig.<domain>/mail/config-v1.1.xml`. It holds no user-specific values, so it works for every user of the domain.",
+				Computed: true,
+			},
+			"mobileconfig": schema.StringAttribute{
+				MarkdownDescription: "An Apple configuration profile (`.mobileconfig`) with the mail, calendar and contacts accounts of the user, with `render_mobileconfig`. " +
+					"It holds no password, devices ask for it when the profile is installed. The profile is unsigned.",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Identifier for this data source instance (same as username).",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ClientSettingsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ClientSettingsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Purelymail spells the domain of user names in lowercase
+	userName := normalizedOrRaw(normalizeMailboxName, data.UserName.ValueString())
+	domainName, ok := emailDomain(userName)
+	if !ok && data.RenderAutoconfig.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user_name"),
+			"Invalid User Name",
+			fmt.Sprintf("An autoconfig file is served for a domain, but %q has none. Use a full email address or drop render_autoconfig.", userName),
+		)
+		return
+	}
+
+	displayName := userName
+	if !data.DisplayName.IsNull() {
+		displayName = data.DisplayName.ValueString()
+	}
+
+	data.Username = types.StringValue(userName)
+	data.CaldavUrl = types.StringValue(purelymailDavUrl)
+	data.CarddavUrl = types.StringValue(purelymailDavUrl)
+	data.WebmailUrl = types.StringValue(purelymailWebmailUrl)
+	data.Id = types.StringValue(userName)
+
+	for _, server := range []struct {
+		target *types.Object
+		server clientServer
+	}{
+		{&data.Imap, purelymailImap},
+		{&data.Smtp, purelymailSmtp},
+		{&data.Pop3, purelymailPop3},
+	} {
+		value, diags := types.ObjectValueFrom(ctx, clientServerAttrTypes, ClientServerModel{
+			Hostname: types.StringValue(server.server.Hostname),
+			Port:     types.Int64Value(server.server.Port),
+			Security: types.StringValue(server.server.Security),
+		})
+		resp.Diagnostics.Append(diags...)
+		*server.target = value
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings := clientSettings{
+		UserName:    userName,
+		DomainName:  domainName,
+		DisplayName: displayName,
+		Imap:        purelymailImap,
+		Smtp:        purelymailSmtp,
+		Pop3:        purelymailPop3,
+		Dav:         purelymailDav,
+		DavPath:     purelymailDavPath,
+		DavUrl:      purelymailDavUrl,
+	}
+
+	data.AutoconfigXml = types.StringNull()
+	if data.RenderAutoconfig.ValueBool() {
+		rendered, err := renderClientSettings(autoconfigTemplate, settings)
+		if err != nil {
+			resp.Diagnostics.AddError("Render Error", fmt.Sprintf("Unable to render autoconfig file: %s", err))
+			return
+		}
+		data.AutoconfigXml = types.StringValue(rendered)
+	}
+
+	data.Mobileconfig = types.StringNull()
+	if data.RenderMobileconfig.ValueBool() {
+		rendered, err := renderClientSettings(mobileconfigTemplate, settings)
+		if err != nil {
+			resp.Diagnostics.AddError("Render Error", fmt.Sprintf("Unable to render configuration profile: %s", err))
+			return
+		}
+		data.Mobileconfig = types.StringValue(rendered)
+	}
+
+	tflog.Trace(ctx, "read purelymail_client_settings data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// clientSettings are the values of the client configuration templates.
+type clientSettings struct {
+	UserName    string
+	DomainName  string
+	DisplayName string
+	Imap        clientServer
+	Smtp        clientServer
+	Pop3        clientServer
+	Dav         clientServer
+	DavPath     string
+	DavUrl      string
+}
+
+// UUID returns a UUID for a payload of the configuration profile. It is
+// derived from the user name, so the profile is the same on every read and
+// installing it again replaces the accounts instead of adding them twice.
+func (s clientSettings) UUID(payload string) string {
+	sum := sha256.Sum256([]byte(s.UserName + "\x00" + payload))
+	h := strings.ToUpper(hex.EncodeToString(sum[:16]))
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// Identifier returns the reverse-DNS identifier of a payload of the
+// configuration profile.
+func (s clientSettings) Identifier(payload string) string {
+	return "com.purelymail.profile." + s.UUID("profile") + "." + payload
+}
+
+// renderClientSettings renders a client configuration template.
+func renderClientSettings(tmpl *template.Template, settings clientSettings) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, settings); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// clientSettingsFuncs escape values for the XML of the templates.
+var clientSettingsFuncs = template.FuncMap{
+	"xml": func(s string) (string, error) {
+		var buf strings.Builder
+		err := xml.EscapeText(&buf, []byte(s))
+		return buf.String(), err
+	},
+	"upper": strings.ToUpper,
+}
+
+// autoconfigTemplate is a Thunderbird autoconfig file, see
+// https://wiki.mozilla.org/Thunderbird:Autoconfiguration:ConfigFileFormat.
+var autoconfigTemplate = template.Must(template.New("autoconfig").Funcs(clientSettingsFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<clientConfig version="1.1">
+  <emailProvider id="{{ xml .DomainName }}">
+    <domain>{{ xml .DomainName }}</domain>
+    <displayName>{{ xml .DomainName }} (Purelymail)</displayName>
+    <displayShortName>{{ xml .DomainName }}</displayShortName>
+    <incomingServer type="imap">
+      <hostname>{{ .Imap.Hostname }}</hostname>
+      <port>{{ .Imap.Port }}</port>
+      <socketType>{{ upper .Imap.Security }}</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </incomingServer>
+    <incomingServer type="pop3">
+      <hostname>{{ .Pop3.Hostname }}</hostname>
+      <port>{{ .Pop3.Port }}</port>
+      <socketType>{{ upper .Pop3.Security }}</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </incomingServer>
+    <outgoingServer type="smtp">
+      <hostname>{{ .Smtp.Hostname }}</hostname>
+      <port>{{ .Smtp.Port }}</port>
+      <socketType>{{ upper .Smtp.Security }}</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </outgoingServer>
+  </emailProvider>
+  <addressBook type="carddav">
+    <username>%EMAILADDRESS%</username>
+    <authentication system="http">password-cleartext</authentication>
+    <serverURL>{{ xml .DavUrl }}</serverURL>
+  </addressBook>
+  <calendar type="caldav">
+    <username>%EMAILADDRESS%</username>
+    <authentication system="http">password-cleartext</authentication>
+    <serverURL>{{ xml .DavUrl }}</serverURL>
+  </calendar>
+</clientConfig>
+`))
+
+// mobileconfigTemplate is an Apple configuration profile with mail, CalDAV
+// and CardDAV payloads, see
+// https://developer.apple.com/documentation/devicemanagement/mail.
+var mobileconfigTemplate = template.Must(template.New("mobileconfig").Funcs(clientSettingsFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>PayloadContent</key>
+  <array>
+    <dict>
+      <key>EmailAccountDescription</key>
+      <string>{{ xml .UserName }}</string>
+      <key>EmailAccountName</key>
+      <string>{{ xml .DisplayName }}</string>
+      <key>EmailAccountType</key>
+      <string>EmailTypeIMAP</string>
+      <key>EmailAddress</key>
+      <string>{{ xml .UserName }}</string>
+      <key>IncomingMailServerAuthentication</key>
+      <string>EmailAuthPassword</string>
+      <key>IncomingMailServerHostName</key>
+      <string>{{ .Imap.Hostname }}</string>
+      <key>IncomingMailServerPortNumber</key>
+      <integer>{{ .Imap.Port }}</integer>
+      <key>IncomingMailServerUseSSL</key>
+      <true/>
+      <key>IncomingMailServerUsername</key>
+      <string>{{ xml .UserName }}</string>
+      <key>OutgoingMailServerAuthentication</key>
+      <string>EmailAuthPassword</string>
+      <key>OutgoingMailServerHostName</key>
+      <string>{{ .Smtp.Hostname }}</string>
+      <key>OutgoingMailServerPortNumber</key>
+      <integer>{{ .Smtp.Port }}</integer>
+      <key>OutgoingMailServerUseSSL</key>
+      <true/>
+      <key>OutgoingMailServerUsername</key>
+      <string>{{ xml .UserName }}</string>
+      <key>OutgoingPasswordSameAsIncomingPassword</key>
+      <true/>
+      <key>PayloadDisplayName</key>
+      <string>Mail</string>
+      <key>PayloadIdentifier</key>
+      <string>{{ .Identifier "mail" }}</string>
+      <key>PayloadType</key>
+      <string>com.apple.mail.managed</string>
+      <key>PayloadUUID</key>
+      <string>{{ .UUID "mail" }}</string>
+      <key>PayloadVersion</key>
+      <integer>1</integer>
+    </dict>
+    <dict>
+      <key>CalDAVAccountDescription</key>
+      <string>{{ xml .UserName }}</string>
+      <key>CalDAVHostName</key>
+      <string>{{ xml .Dav.Hostname }}</string>
+      <key>CalDAVPort</key>
+      <integer>{{ .Dav.Port }}</integer>
+      <key>CalDAVPrincipalURL</key>
+      <string>{{ xml .DavPath }}</string>
+      <key>CalDAVUseSSL</key>
+      <true/>
+      <key>CalDAVUsername</key>
+      <string>{{ xml .UserName }}</string>
+      <key>PayloadDisplayName</key>
+      <string>Calendars</string>
+      <key>PayloadIdentifier</key>
+      <string>{{ .Identifier "caldav" }}</string>
+      <key>PayloadType</key>
+      <string>com.apple.caldav.account</string>
+      <key>PayloadUUID</key>
+      <string>{{ .UUID "caldav" }}</string>
+      <key>PayloadVersion</key>
+      <integer>1</integer>
+    </dict>
+    <dict>
+      <key>CardDAVAccountDescription</key>
+      <string>{{ xml .UserName }}</string>
+      <key>CardDAVHostName</key>
+      <string>{{ xml .Dav.Hostname }}</string>
+      <key>CardDAVPort</key>
+      <integer>{{ .Dav.Port }}</integer>
+      <key>CardDAVPrincipalURL</key>
+      <string>{{ xml .DavPath }}</string>
+      <key>CardDAVUseSSL</key>
+      <true/>
+      <key>CardDAVUsername</key>
+      <string>{{ xml .UserName }}</string>
+      <key>PayloadDisplayName</key>
+      <string>Contacts</string>
+      <key>PayloadIdentifier</key>
+      <string>{{ .Identifier "carddav" }}</string>
+      <key>PayloadType</key>
+      <string>com.apple.carddav.account</string>
+      <key>PayloadUUID</key>
+      <string>{{ .UUID "carddav" }}</string>
+      <key>PayloadVersion</key>
+      <integer>1</integer>
+    </dict>
+  </array>
+  <key>PayloadDescription</key>
+  <string>Mail, calendars and contacts of {{ xml .UserName }} on Purelymail.</string>
+  <key>PayloadDisplayName</key>
+  <string>{{ xml .UserName }}</string>
+  <key>PayloadIdentifier</key>
+  <string>{{ .Identifier "profile" }}</string>
+  <key>PayloadRemovalDisallowed</key>
+  <false/>
+  <key>PayloadType</key>
+  <string>Configuration</string>
+  <key>PayloadUUID</key>
+  <string>{{ .UUID "profile" }}</string>
+  <key>PayloadVersion</key>
+  <integer>1</integer>
+</dict>
+</plist>
+`))
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccClientSettingsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Settings only, without rendered files
+			{
+				Config: testAccClientSettingsDataSourceConfig(`user_name = "Alice@Example.COM"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("username"),
+						knownvalue.StringExact("alice@example.com"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("imap"),
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"hostname": knownvalue.StringExact("imap.purelymail.com"),
+							"port":     knownvalue.Int64Exact(993),
+							"security": knownvalue.StringExact("ssl"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("smtp").AtMapKey("port"),
+						knownvalue.Int64Exact(465),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("pop3").AtMapKey("hostname"),
+						knownvalue.StringExact("pop.purelymail.com"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("caldav_url"),
+						knownvalue.StringExact("https://purelymail.com/webdav/"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("autoconfig_xml"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("mobileconfig"),
+						knownvalue.Null(),
+					),
+				},
+			},
+			// An autoconfig file needs a domain
+			{
+				Config: testAccClientSettingsDataSourceConfig(`
+  user_name         = "alice"
+  render_autoconfig = true
+`),
+				ExpectError: regexp.MustCompile(`Invalid User Name`),
+			},
+			// Rendered autoconfig file and configuration profile
+			{
+				Config: testAccClientSettingsDataSourceConfig(`
+  user_name           = "alice@example.com"
+  display_name        = "Alice & Co"
+  render_autoconfig   = true
+  render_mobileconfig = true
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("autoconfig_xml"),
+						knownvalue.StringRegexp(regexp.MustCompile(`<domain>example\.com</domain>`)),
+					),
+					statecheck.ExpectKnownValue(
+						"data.purelymail_client_settings.test",
+						tfjsonpath.New("mobileconfig"),
+						knownvalue.StringRegexp(regexp.MustCompile(`<string>Alice &amp; Co</string>`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccClientSettingsDataSourceConfig(arguments string) string {
+	return `
+provider "purelymail" {
+  api_token = "test-token"
+}
+
+data "purelymail_client_settings" "test" {
+  ` + arguments + `
+}
+`
+}
+
+func TestRenderClientSettings(t *testing.T) {
+	settings := clientSettings{
+		UserName:    "alice@example.com",
+		DomainName:  "example.com",
+		DisplayName: "Alice <Admin>",
+		Imap:        purelymailImap,
+		Smtp:        purelymailSmtp,
+		Pop3:        purelymailPop3,
+		Dav:         purelymailDav,
+		DavPath:     purelymailDavPath,
+		DavUrl:      purelymailDavUrl,
+	}
+
+	for name, tmpl := range map[string]*template.Template{"autoconfig": autoconfigTemplate, "mobileconfig": mobileconfigTemplate} {
+		rendered, err := renderClientSettings(tmpl, settings)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		// Both files must be well-formed XML
+		decoder := xml.NewDecoder(strings.NewReader(rendered))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				if !errors.Is(err, io.EOF) {
+					t.Errorf("%s: invalid XML: %s", name, err)
+				}
+				break
+			}
+		}
+	}
+
+	// CalDAV and CardDAV accounts take the host, port and path separately
+	rendered, err := renderClientSettings(mobileconfigTemplate, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, protocol := range []string{"CalDAV", "CardDAV"} {
+		for key, value := range map[string]string{
+			"HostName":     "<string>purelymail.com</string>",
+			"Port":         "<integer>443</integer>",
+			"PrincipalURL": "<string>/webdav/</string>",
+		} {
+			want := "<key>" + protocol + key + "</key>\n      " + value
+			if !strings.Contains(rendered, want) {
+				t.Errorf("expected %q in configuration profile", want)
+			}
+		}
+	}
+
+	// Payload UUIDs are stable and differ between payloads
+	if settings.UUID("mail") != settings.UUID("mail") || settings.UUID("mail") == settings.UUID("caldav") {
+		t.Errorf("expected stable and distinct payload UUIDs")
+	}
+	if !regexp.MustCompile(`^[0-9A-F]{8}-[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{12}$`).MatchString(settings.UUID("mail")) {
+		t.Errorf("invalid UUID %s", settings.UUID("mail"))
+	}
+}
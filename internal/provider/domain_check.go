@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/agext/levenshtein"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// accountDomainCache holds the domains of the account, including shared ones,
+// per API client. Every provider configuration has its own client, so plan-time
+// checks list the domains only once per run.
+var accountDomainCache = struct {
+	sync.Mutex
+	domains map[*api.Client][]api.ApiDomainInfo
+}{
+	domains: make(map[*api.Client][]api.ApiDomainInfo),
+}
+
+// accountDomains returns the owned and shared domains of the account, listing
+// them on first use.
+func accountDomains(ctx context.Context, client *api.Client) ([]api.ApiDomainInfo, error) {
+	accountDomainCache.Lock()
+	defer accountDomainCache.Unlock()
+
+	if domains, ok := accountDomainCache.domains[client]; ok {
+		return domains, nil
+	}
+
+	domains, err := listDomains(ctx, client, true)
+	if err != nil {
+		return nil, err
+	}
+	accountDomainCache.domains[client] = domains
+
+	return domains, nil
+}
+
+// forgetAccountDomains drops the cached domains after domains are added or
+// removed.
+func forgetAccountDomains(client *api.Client) {
+	accountDomainCache.Lock()
+	defer accountDomainCache.Unlock()
+
+	delete(accountDomainCache.domains, client)
+}
+
+// lookupAccountDomain reports whether the account owns or shares domainName.
+// If it doesn't, the owned domain with the smallest edit distance is suggested,
+// unless none is close enough to be a typo.
+func lookupAccountDomain(ctx context.Context, client *api.Client, domainName string) (bool, string, error) {
+	domains, err := accountDomains(ctx, client)
+	if err != nil {
+		return false, "", err
+	}
+
+	suggestion := ""
+	best := len(domainName)/3 + 1
+	for _, domain := range domains {
+		if domain.Name == nil {
+			continue
+		}
+		if sameDomainName(*domain.Name, domainName) {
+			return true, "", nil
+		}
+		if domain.IsShared != nil && *domain.IsShared {
+			continue
+		}
+		if distance := levenshtein.Distance(strings.ToLower(domainName), strings.ToLower(*domain.Name), nil); distance <= best {
+			best = distance
+			suggestion = *domain.Name
+		}
+	}
+
+	return false, suggestion, nil
+}
+
+// emailDomain returns the domain part of an email address, or false if it has
+// none.
+func emailDomain(address string) (string, bool) {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return "", false
+	}
+
+	return address[at+1:], true
+}
+
+// unknownDomainMessage describes a domain that is neither owned by nor shared
+// with the account, suggesting the closest owned domain if there is one.
+func unknownDomainMessage(attribute string, domainName string, suggestion string) string {
+	message := fmt.Sprintf("Domain %s of %s is not a domain of this account.", domainName, attribute)
+	if suggestion != "" {
+		message += fmt.Sprintf(" Did you mean %s?", suggestion)
+	}
+
+	return message
+}
+
+// warnUnknownDomain warns at plan time when domainName is neither owned by nor
+// shared with the account. It is only a warning because the domain may be
+// added by the same apply.
+func warnUnknownDomain(ctx context.Context, client *api.Client, attrPath path.Path, domainName types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if client == nil || domainName.IsNull() || domainName.IsUnknown() || domainName.ValueString() == "" {
+		return diags
+	}
+
+	found, suggestion, err := lookupAccountDomain(ctx, client, domainName.ValueString())
+	if err != nil {
+		diags.AddWarning("Domain Check Skipped", fmt.Sprintf("Unable to check the domains of this account: %s", err))
+		return diags
+	}
+	if !found {
+		diags.AddAttributeWarning(
+			attrPath,
+			"Unknown Domain",
+			unknownDomainMessage(attrPath.String(), domainName.ValueString(), suggestion)+
+				" Creating it will fail unless the domain is added before it, for example by a purelymail_domain resource in the same configuration.",
+		)
+	}
+
+	return diags
+}
+
+// warnUnknownUserDomain is warnUnknownDomain for the domain part of a user
+// name. User names without a domain part can't be checked.
+func warnUnknownUserDomain(ctx context.Context, client *api.Client, attrPath path.Path, userName types.String) diag.Diagnostics {
+	if userName.IsNull() || userName.IsUnknown() {
+		return nil
+	}
+
+	domainName, ok := emailDomain(userName.ValueString())
+	if !ok {
+		return nil
+	}
+
+	return warnUnknownDomain(ctx, client, attrPath, types.StringValue(domainName))
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestLookupAccountDomain(t *testing.T) {
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"example.com", "company.org"} {
+		httpResp, err := client.AddDomain(ctx, api.AddDomainRequest{DomainName: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		httpResp.Body.Close()
+	}
+	mockServer.AddSharedDomain("purelymail.net")
+
+	cases := []struct {
+		domainName string
+		found      bool
+		suggestion string
+	}{
+		{domainName: "example.com", found: true},
+		{domainName: "Example.COM", found: true},
+		{domainName: "purelymail.net", found: true},
+		{domainName: "exampel.com", suggestion: "example.com"},
+		{domainName: "compnay.org", suggestion: "company.org"},
+		// Shared domains aren't suggested
+		{domainName: "purelymail.com"},
+		{domainName: "unrelated.net"},
+	}
+	for _, c := range cases {
+		found, suggestion, err := lookupAccountDomain(ctx, client, c.domainName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != c.found || suggestion != c.suggestion {
+			t.Errorf("lookupAccountDomain(%q) = %t, %q, want %t, %q", c.domainName, found, suggestion, c.found, c.suggestion)
+		}
+	}
+
+	// Domains are listed once per client
+	mockServer.InjectFault("ListDomains", 0)
+	if found, _, err := lookupAccountDomain(ctx, client, "example.com"); err != nil || !found {
+		t.Errorf("expected the cached domains to be used, got %t, %v", found, err)
+	}
+}
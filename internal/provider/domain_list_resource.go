@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &DomainListResource{}
+var _ list.ListResourceWithConfigure = &DomainListResource{}
+
+func NewDomainListResource() list.ListResource {
+	return &DomainListResource{}
+}
+
+// DomainListResource defines the list resource implementation.
+type DomainListResource struct {
+	client *api.Client
+}
+
+// DomainListResourceModel describes the list resource config model.
+type DomainListResourceModel struct {
+	PassesDnsChecks types.Bool `tfsdk:"passes_dns_checks"`
+}
+
+func (r *DomainListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain"
+}
+
+func (r *DomainListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the domains owned by the account. Domains shared with the account are not listed, as they can't be managed.",
+
+		Attributes: map[string]schema.Attribute{
+			"passes_dns_checks": schema.BoolAttribute{
+				MarkdownDescription: "Only list domains that pass all DNS checks (MX, SPF, DKIM and DMARC) if true, or that fail at least one of them if false.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *DomainListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *DomainListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config DomainListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	domains, err := listDomains(ctx, r.client, false)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list domains: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = limitListResults(req.Limit, func(push func(list.ListResult) bool) {
+		for _, domain := range domains {
+			if domain.Name == nil {
+				continue
+			}
+			if !config.PassesDnsChecks.IsNull() && passesDnsChecks(domain) != config.PassesDnsChecks.ValueBool() {
+				continue
+			}
+
+			result := r.listResult(ctx, req, &domain)
+			if !push(result) || result.Diagnostics.HasError() {
+				return
+			}
+		}
+	})
+}
+
+// listResult returns the list result of a domain.
+func (r *DomainListResource) listResult(ctx context.Context, req list.ListRequest, domain *api.ApiDomainInfo) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = *domain.Name
+
+	data := DomainResourceModel{Name: NewDomainNameValue(*domain.Name)}
+	if req.IncludeResource {
+		var diags diag.Diagnostics
+		data, diags = importedResource[DomainResourceModel](ctx, result.Resource, path.Root("name"), *domain.Name)
+		result.Diagnostics.Append(diags...)
+		if result.Diagnostics.HasError() {
+			return result
+		}
+
+		data.Id = types.StringValue(*domain.Name)
+		if err := setDomainInfo(ctx, &data, domain); err != nil {
+			return listResultError("Read Error", fmt.Sprintf("Unable to read domain %s: %s", *domain.Name, err))
+		}
+		setImportedDomainDefaults(&data)
+
+		result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+	}
+
+	result.Diagnostics.Append(result.Identity.Set(ctx, domainIdentity(&data))...)
+
+	return result
+}
+
+// passesDnsChecks reports whether a domain passes all DNS checks.
+func passesDnsChecks(domain api.ApiDomainInfo) bool {
+	summary := domain.DnsSummary
+	if summary == nil {
+		return false
+	}
+
+	for _, passes := range []*bool{summary.PassesMx, summary.PassesSpf, summary.PassesDkim, summary.PassesDmarc} {
+		if passes == nil || !*passes {
+			return false
+		}
+	}
+
+	return true
+}
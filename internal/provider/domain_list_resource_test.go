@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestAccDomainListResource(t *testing.T) {
+	mockServer := mock.NewServer()
+	mockServer.AddSharedDomain("shared.example")
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			// example.com passes all DNS checks after the recheck, example.org
+			// keeps failing DKIM and DMARC
+			{
+				Config: testAccDomainListResourceDomainsConfig(ts.URL),
+			},
+			// Shared domains are not listed
+			{
+				Query:  true,
+				Config: testAccDomainListResourceConfig("", true),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_domain.test", 2),
+					querycheck.ExpectResourceKnownValues("purelymail_domain.test", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"name": knownvalue.StringExact("example.org"),
+					}), []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("allow_account_reset"),
+							KnownValue: knownvalue.Bool(true),
+						},
+						{
+							Path:       tfjsonpath.New("dns_summary").AtMapKey("passes_dkim"),
+							KnownValue: knownvalue.Bool(false),
+						},
+						{
+							Path:       tfjsonpath.New("deletion_protection"),
+							KnownValue: knownvalue.Bool(false),
+						},
+					}),
+				},
+			},
+			{
+				Query:  true,
+				Config: testAccDomainListResourceConfig("passes_dns_checks = true", false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_domain.test", 1),
+					querycheck.ExpectIdentity("purelymail_domain.test", map[string]knownvalue.Check{
+						"name": knownvalue.StringExact("example.com"),
+					}),
+				},
+			},
+			{
+				Query:  true,
+				Config: testAccDomainListResourceConfig("passes_dns_checks = false", false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_domain.test", 1),
+					querycheck.ExpectIdentity("purelymail_domain.test", map[string]knownvalue.Check{
+						"name": knownvalue.StringExact("example.org"),
+					}),
+				},
+			},
+		},
+	})
+}
+
+func testAccDomainListResourceDomainsConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "com" {
+	name        = "example.com"
+	recheck_dns = true
+}
+
+resource "purelymail_domain" "org" {
+	name                = "example.org"
+	allow_account_reset = true
+}
+`, endpoint)
+}
+
+// The query configs have no provider block, the one of the resources config
+// is used.
+func testAccDomainListResourceConfig(filter string, includeResource bool) string {
+	return fmt.Sprintf(`
+list "purelymail_domain" "test" {
+	provider         = purelymail
+	include_resource = %[2]t
+
+	config {
+		%[1]s
+	}
+}
+`, filter, includeResource)
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// purelymailMxHost is the mail server the MX records of every Purelymail domain must point at.
+const purelymailMxHost = "mailserver.purelymail.com"
+
+const (
+	defaultPreflightTimeout  = 5 * time.Minute
+	defaultPreflightInterval = 10 * time.Second
+)
+
+// newPreflightResolver returns a resolver that sends all queries to address
+// (host:port), or the system resolver if address is empty.
+func newPreflightResolver(address string) *net.Resolver {
+	if address == "" {
+		return net.DefaultResolver
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// checkDomainDns resolves the records Purelymail requires before a domain can
+// be added. It returns one line per record that is missing or wrong.
+func checkDomainDns(ctx context.Context, resolver *net.Resolver, domain string, ownershipCode string) []string {
+	var problems []string
+
+	txts, err := resolver.LookupTXT(ctx, domain)
+	switch {
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("TXT %s: missing ownership proof %q (lookup failed: %s)", domain, ownershipCode, err))
+	case !slices.Contains(txts, ownershipCode):
+		problems = append(problems, fmt.Sprintf("TXT %s: missing ownership proof %q (found: %s)", domain, ownershipCode, formatRecords(txts)))
+	}
+
+	mxs, err := resolver.LookupMX(ctx, domain)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("MX %s: missing record for %s (lookup failed: %s)", domain, purelymailMxHost, err))
+	} else {
+		var hosts []string
+		for _, mx := range mxs {
+			hosts = append(hosts, strings.ToLower(strings.TrimSuffix(mx.Host, ".")))
+		}
+		if !slices.Contains(hosts, purelymailMxHost) {
+			problems = append(problems, fmt.Sprintf("MX %s: missing record for %s (found: %s)", domain, purelymailMxHost, formatRecords(hosts)))
+		}
+	}
+
+	return problems
+}
+
+// waitForDomainDns retries checkDomainDns until it reports no problems or the
+// timeout expires, in which case the problems of the last attempt are returned.
+func waitForDomainDns(ctx context.Context, resolver *net.Resolver, domain string, ownershipCode string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		problems := checkDomainDns(ctx, resolver, domain, ownershipCode)
+		if len(problems) == 0 {
+			return nil
+		}
+
+		if time.Now().Add(defaultPreflightInterval).After(deadline) {
+			return fmt.Errorf("DNS records for %s are not ready after %s:\n  %s", domain, timeout, strings.Join(problems, "\n  "))
+		}
+
+		tflog.Debug(ctx, "waiting for domain DNS records", map[string]interface{}{
+			"domain":   domain,
+			"problems": problems,
+		})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(defaultPreflightInterval):
+		}
+	}
+}
+
+func formatRecords(records []string) string {
+	if len(records) == 0 {
+		return "no records"
+	}
+	quoted := make([]string, len(records))
+	for i, record := range records {
+		quoted[i] = fmt.Sprintf("%q", record)
+	}
+	return strings.Join(quoted, ", ")
+}
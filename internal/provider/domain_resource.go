@@ -4,17 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
@@ -23,6 +33,9 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DomainResource{}
 var _ resource.ResourceWithImportState = &DomainResource{}
+var _ resource.ResourceWithModifyPlan = &DomainResource{}
+var _ resource.ResourceWithUpgradeState = &DomainResource{}
+var _ resource.ResourceWithIdentity = &DomainResource{}
 
 func NewDomainResource() resource.Resource {
 	return &DomainResource{}
@@ -35,13 +48,22 @@ type DomainResource struct {
 
 // DomainResourceModel describes the resource data model.
 type DomainResourceModel struct {
-	Name                  types.String `tfsdk:"name"`
-	AllowAccountReset     types.Bool   `tfsdk:"allow_account_reset"`
-	SymbolicSubaddressing types.Bool   `tfsdk:"symbolic_subaddressing"`
-	RecheckDns            types.Bool   `tfsdk:"recheck_dns"`
-	IsShared              types.Bool   `tfsdk:"is_shared"`
-	DnsSummary            types.Object `tfsdk:"dns_summary"`
-	Id                    types.String `tfsdk:"id"`
+	Name                  DomainNameValue `tfsdk:"name"`
+	AllowAccountReset     types.Bool      `tfsdk:"allow_account_reset"`
+	SymbolicSubaddressing types.Bool      `tfsdk:"symbolic_subaddressing"`
+	RecheckDns            types.Bool      `tfsdk:"recheck_dns"`
+	RecheckTriggers       types.Map       `tfsdk:"recheck_triggers"`
+	IsShared              types.Bool      `tfsdk:"is_shared"`
+	DnsSummary            types.Object    `tfsdk:"dns_summary"`
+	WaitForDns            types.Object    `tfsdk:"wait_for_dns"`
+	PreflightDns          types.Bool      `tfsdk:"preflight_dns"`
+	PreflightResolver     types.String    `tfsdk:"preflight_resolver"`
+	PreflightTimeout      types.String    `tfsdk:"preflight_timeout"`
+	DeletionProtection    types.Bool      `tfsdk:"deletion_protection"`
+	ForceDestroy          types.Bool      `tfsdk:"force_destroy"`
+	AdoptExisting         types.Bool      `tfsdk:"adopt_existing"`
+	Id                    types.String    `tfsdk:"id"`
+	Timeouts              timeouts.Value  `tfsdk:"timeouts"`
 }
 
 // DnsSummaryModel describes the DNS summary nested object.
@@ -52,20 +74,60 @@ type DnsSummaryModel struct {
 	PassesDmarc types.Bool `tfsdk:"passes_dmarc"`
 }
 
+// WaitForDnsModel describes the wait_for_dns block.
+type WaitForDnsModel struct {
+	Require  types.List   `tfsdk:"require"`
+	Timeout  types.String `tfsdk:"timeout"`
+	Interval types.String `tfsdk:"interval"`
+}
+
+// dnsCheckNames lists the DNS checks reported in a domain's DNS summary.
+var dnsCheckNames = []string{"mx", "spf", "dkim", "dmarc"}
+
+const (
+	defaultWaitForDnsTimeout  = 15 * time.Minute
+	defaultWaitForDnsInterval = 30 * time.Second
+)
+
+// DomainResourceIdentityModel describes the resource identity.
+type DomainResourceIdentityModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+// domainIdentity returns the identity of a domain, with the name normalized.
+func domainIdentity(data *DomainResourceModel) DomainResourceIdentityModel {
+	return DomainResourceIdentityModel{
+		Name: types.StringValue(normalizedOrRaw(normalizeDomainName, data.Name.ValueString())),
+	}
+}
+
 func (r *DomainResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_domain"
 }
 
+func (r *DomainResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				Description:       "The domain name.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *DomainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a Purelymail domain. Requires DNS ownership verification.",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The domain name (e.g., example.com).",
+				CustomType:          DomainNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessSemanticallyEqual(DomainNameType{}),
 				},
 			},
 			"allow_account_reset": schema.BoolAttribute{
@@ -82,7 +144,46 @@ func (r *DomainResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"recheck_dns": schema.BoolAttribute{
 				MarkdownDescription: "Set to true to force DNS recheck on update. Not stored in state.",
+				DeprecationMessage:  "recheck_dns only takes effect when another setting changes and is sent again on every later update. Use recheck_triggers instead.",
+				Optional:            true,
+			},
+			"recheck_triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary map of values that, when changed, make Purelymail recheck the DNS records of the domain and refresh `dns_summary`. Typically keyed on the DNS record resources managing the domain.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"preflight_dns": schema.BoolAttribute{
+				MarkdownDescription: "Whether to resolve the ownership proof TXT record and the MX records before adding the domain. Creation is retried until the records are correct or `preflight_timeout` expires.",
+				Optional:            true,
+			},
+			"preflight_resolver": schema.StringAttribute{
+				MarkdownDescription: "DNS server used by the preflight check, as 'host:port'. Defaults to the system resolver.",
+				Optional:            true,
+			},
+			"preflight_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for the DNS records to be correct, as a duration like '5m'. Defaults to '5m'.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether to refuse deleting the domain, including replacements caused by renaming it. Must be set to false and applied before the domain can be destroyed.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether to delete the domain even if users or routing rules still exist on it. Deleting a domain deletes all of its users and their mail.",
 				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to take over a domain that is already on the account instead of failing. The existing domain's settings are changed to match the configuration, and `preflight_dns` is skipped.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
 			},
 			"is_shared": schema.BoolAttribute{
 				MarkdownDescription: "Whether this is a shared domain.",
@@ -124,6 +225,39 @@ func (r *DomainResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"wait_for_dns": schema.SingleNestedBlock{
+				MarkdownDescription: "Wait after create and update until the domain passes the required DNS checks. DNS is rechecked on every poll. If the checks do not pass before the timeout, the apply fails with a report of each record. " +
+					"A timeout while creating the domain leaves it tainted, as Terraform taints every resource whose create fails, so the next apply destroys and adds it again. " +
+					"Run `terraform untaint` on the domain to keep it instead; the checks are then waited for again on its next update.",
+				Attributes: map[string]schema.Attribute{
+					"require": schema.ListAttribute{
+						MarkdownDescription: "DNS checks that must pass. Valid values: 'mx', 'spf', 'dkim', 'dmarc'. Defaults to all of them.",
+						Optional:            true,
+						ElementType:         types.StringType,
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(stringvalidator.OneOf(dnsCheckNames...)),
+						},
+					},
+					"timeout": schema.StringAttribute{
+						MarkdownDescription: "How long to wait for the DNS checks to pass, as a duration like '15m'. Defaults to '15m'.",
+						Optional:            true,
+						Validators: []validator.String{
+							durationValidator{},
+						},
+					},
+					"interval": schema.StringAttribute{
+						MarkdownDescription: "How long to wait between DNS rechecks, as a duration like '30s'. Defaults to '30s'.",
+						Optional:            true,
+						Validators: []validator.String{
+							durationValidator{},
+						},
+					},
+				},
+			},
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
 	}
 }
 
@@ -152,21 +286,75 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	// Add domain via API
-	addReq := api.AddDomainRequest{
-		DomainName: data.Name.ValueString(),
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	defer finish(&resp.Diagnostics)
 
-	httpResp, err := r.client.AddDomain(ctx, addReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to add domain: %s", err))
-		return
+	adopted := false
+	if data.AdoptExisting.ValueBool() {
+		setStep(ctx, "check for an existing domain")
+		existing, err := findDomain(ctx, r.client, data.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for existing domain: %s", err))
+			return
+		}
+		if existing != nil {
+			resp.Diagnostics.AddWarning(
+				"Existing Domain Adopted",
+				fmt.Sprintf("Domain %s was already on the account and was adopted. Its settings were changed to match the configuration.", data.Name.ValueString()),
+			)
+			adopted = true
+		}
 	}
-	defer httpResp.Body.Close()
 
-	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to add domain (status: %d)", httpResp.StatusCode))
-		return
+	if !adopted {
+		// Check DNS locally first, the API only reports a status code
+		if data.PreflightDns.ValueBool() {
+			setStep(ctx, "check DNS records before adding the domain")
+			code, err := getOwnershipCode(ctx, r.client)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get ownership code: %s", err))
+				return
+			}
+
+			timeout := defaultPreflightTimeout
+			if !data.PreflightTimeout.IsNull() {
+				timeout, err = time.ParseDuration(data.PreflightTimeout.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(path.Root("preflight_timeout"), "Invalid Duration", err.Error())
+					return
+				}
+			}
+
+			resolver := newPreflightResolver(data.PreflightResolver.ValueString())
+			if err := waitForDomainDns(ctx, resolver, data.Name.ValueString(), code, timeout); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("preflight_dns"), "DNS Preflight Failed", err.Error())
+				return
+			}
+		}
+
+		// Add domain via API
+		addReq := api.AddDomainRequest{
+			DomainName: data.Name.ValueString(),
+		}
+
+		setStep(ctx, "add domain")
+		httpResp, err := r.client.AddDomain(ctx, addReq)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to add domain: %s", err))
+			return
+		}
+		defer httpResp.Body.Close()
+
+		if httpResp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(httpResp.Body)
+			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to add domain (status: %d): %s", httpResp.StatusCode, strings.TrimSpace(string(body))))
+			return
+		}
+		forgetAccountDomains(r.client)
 	}
 
 	// Set ID
@@ -186,9 +374,19 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	// Wait for DNS if requested. The domain exists at this point, so it is
+	// saved even if the checks time out, but Terraform taints it.
+	if err := r.waitForDns(ctx, &data); err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentity(&data))...)
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_dns"), "DNS Verification Failed", err.Error())
+		return
+	}
+
 	tflog.Trace(ctx, "created purelymail_domain resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentity(&data))...)
 }
 
 func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -199,39 +397,75 @@ func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "read", data.Timeouts.Read, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	if err := r.readDomain(ctx, &data); err != nil {
 		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read domain: %s", err))
 		return
 	}
 
+	setImportedDomainDefaults(&data)
+
 	tflog.Trace(ctx, "read purelymail_domain resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentity(&data))...)
 }
 
 func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data DomainResourceModel
+	var state DomainResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "update", data.Timeouts.Update, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	keepPriorSpelling(&data.Name, state.Name)
+
 	// Update domain settings
 	if err := r.updateDomainSettings(ctx, &data); err != nil {
 		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update domain settings: %s", err))
 		return
 	}
 
+	if !data.RecheckTriggers.Equal(state.RecheckTriggers) {
+		if err := r.recheckDns(ctx, data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to recheck DNS: %s", err))
+			return
+		}
+	}
+
 	// Read back domain info
 	if err := r.readDomain(ctx, &data); err != nil {
 		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read domain after update: %s", err))
 		return
 	}
 
+	if err := r.waitForDns(ctx, &data); err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentity(&data))...)
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_dns"), "DNS Verification Failed", err.Error())
+		return
+	}
+
 	tflog.Trace(ctx, "updated purelymail_domain resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, domainIdentity(&data))...)
 }
 
 func (r *DomainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -242,10 +476,46 @@ func (r *DomainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Deletion Protection Enabled",
+			fmt.Sprintf("Domain %s has deletion_protection enabled. Set deletion_protection = false and apply before destroying or replacing it.", data.Name.ValueString()),
+		)
+		return
+	}
+
+	// Deleting a domain also deletes its users, so refuse unless forced
+	if !data.ForceDestroy.ValueBool() {
+		setStep(ctx, "check for users and routing rules on the domain")
+		dependents, err := r.listDomainDependents(ctx, data.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for users and routing rules on domain: %s", err))
+			return
+		}
+		if len(dependents) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("force_destroy"),
+				"Domain Not Empty",
+				fmt.Sprintf("Domain %s still has users or routing rules:\n  %s\nDelete them first, or set force_destroy = true and apply to delete the domain with everything on it.",
+					data.Name.ValueString(), strings.Join(dependents, "\n  ")),
+			)
+			return
+		}
+	}
+
 	deleteReq := api.DeleteDomainRequest{
 		Name: data.Name.ValueString(),
 	}
 
+	setStep(ctx, "delete domain")
 	httpResp, err := r.client.DeleteDomain(ctx, deleteReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete domain: %s", err))
@@ -257,12 +527,102 @@ func (r *DomainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete domain (status: %d)", httpResp.StatusCode))
 		return
 	}
+	forgetAccountDomains(r.client)
 
 	tflog.Trace(ctx, "deleted purelymail_domain resource")
 }
 
 func (r *DomainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("name"), path.Root("name"), req, resp)
+}
+
+func (r *DomainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		r.modifyCreatePlan(ctx, req, resp)
+		return
+	}
+
+	var plan, state DomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A DNS recheck may change the summary
+	if !plan.RecheckTriggers.Equal(state.RecheckTriggers) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("dns_summary"), types.ObjectUnknown(plan.DnsSummary.AttributeTypes(ctx)))...)
+	}
+}
+
+// modifyCreatePlan warns when creating the domain will adopt an existing one.
+func (r *DomainResource) modifyCreatePlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+
+	var plan DomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.AdoptExisting.ValueBool() || plan.Name.IsUnknown() {
+		return
+	}
+
+	existing, err := findDomain(ctx, r.client, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for existing domain: %s", err))
+		return
+	}
+	if existing != nil {
+		resp.Diagnostics.AddWarning(
+			"Existing Domain Will Be Adopted",
+			fmt.Sprintf("Domain %s is already on the account. It will be adopted instead of added, and its settings will be changed to match the configuration.", plan.Name.ValueString()),
+		)
+	}
+}
+
+func (r *DomainResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 had no recheck_triggers, and recheck_dns = true was sent
+		// again with every update. The recheck it asked for has happened, so
+		// drop it from state. Configurations that still set it see one more
+		// update with a recheck, together with the deprecation warning.
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior map[string]json.RawMessage
+				if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+					resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to decode prior purelymail_domain state: %s", err))
+					return
+				}
+
+				var recheckDns *bool
+				if err := json.Unmarshal(prior["recheck_dns"], &recheckDns); len(prior["recheck_dns"]) > 0 && err != nil {
+					resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to decode recheck_dns of prior purelymail_domain state: %s", err))
+					return
+				}
+				if recheckDns != nil && *recheckDns {
+					prior["recheck_dns"] = json.RawMessage("null")
+				}
+				prior["recheck_triggers"] = json.RawMessage("null")
+
+				upgraded, err := json.Marshal(prior)
+				if err != nil {
+					resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to encode upgraded purelymail_domain state: %s", err))
+					return
+				}
+
+				resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+			},
+		},
+	}
 }
 
 // updateDomainSettings updates the domain settings via API.
@@ -286,6 +646,7 @@ func (r *DomainResource) updateDomainSettings(ctx context.Context, data *DomainR
 		updateReq.RecheckDns = &recheckDns
 	}
 
+	setStep(ctx, "update domain settings")
 	httpResp, err := r.client.UpdateDomainSettings(ctx, updateReq)
 	if err != nil {
 		return fmt.Errorf("client error: %w", err)
@@ -301,54 +662,24 @@ func (r *DomainResource) updateDomainSettings(ctx context.Context, data *DomainR
 
 // readDomain reads domain information from the API.
 func (r *DomainResource) readDomain(ctx context.Context, data *DomainResourceModel) error {
-	includeShared := false
-	listReq := api.ListDomainsRequest{
-		IncludeShared: &includeShared,
-	}
-
-	httpResp, err := r.client.ListDomains(ctx, listReq)
-	if err != nil {
-		return fmt.Errorf("unable to list domains: %w", err)
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API returned status %d", httpResp.StatusCode)
-	}
-
-	var listResp api.ListDomainsResponse
-	if err := json.NewDecoder(httpResp.Body).Decode(&listResp); err != nil {
-		return fmt.Errorf("unable to decode response: %w", err)
-	}
-
-	if listResp.Result == nil {
-		return fmt.Errorf("no result in response")
-	}
-	if listResp.Result.Domains == nil {
-		return fmt.Errorf("no domains field in result")
-	}
-	if len(*listResp.Result.Domains) == 0 {
-		return fmt.Errorf("domains list is empty")
-	}
-
-	// Find the domain
-	var foundDomain *api.ApiDomainInfo
+	setStep(ctx, "read domain")
 	targetName := data.Name.ValueString()
-	for _, domain := range *listResp.Result.Domains {
-		if domain.Name != nil && *domain.Name == targetName {
-			domainCopy := domain
-			foundDomain = &domainCopy
-			break
-		}
+	foundDomain, err := findDomain(ctx, r.client, targetName)
+	if err != nil {
+		return err
 	}
-
 	if foundDomain == nil {
 		return fmt.Errorf("domain not found: %s", targetName)
 	}
 
-	// Update the model
 	data.Id = types.StringValue(targetName)
 
+	return setDomainInfo(ctx, data, foundDomain)
+}
+
+// setDomainInfo updates the model with the settings and DNS summary of a
+// domain as the API lists it.
+func setDomainInfo(ctx context.Context, data *DomainResourceModel, foundDomain *api.ApiDomainInfo) error {
 	if foundDomain.AllowAccountReset != nil {
 		data.AllowAccountReset = types.BoolValue(*foundDomain.AllowAccountReset)
 	} else {
@@ -384,3 +715,232 @@ func (r *DomainResource) readDomain(ctx context.Context, data *DomainResourceMod
 
 	return nil
 }
+
+// setImportedDomainDefaults sets the deletion_protection, force_destroy and
+// adopt_existing values that imported domains have none of yet.
+func setImportedDomainDefaults(data *DomainResourceModel) {
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+}
+
+// waitForDns polls the DNS checks of the domain until all checks required by
+// the wait_for_dns block pass or its timeout expires. It does nothing if the
+// block is not configured.
+func (r *DomainResource) waitForDns(ctx context.Context, data *DomainResourceModel) error {
+	if data.WaitForDns.IsNull() || data.WaitForDns.IsUnknown() {
+		return nil
+	}
+
+	var wait WaitForDnsModel
+	if diags := data.WaitForDns.As(ctx, &wait, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return fmt.Errorf("unable to read wait_for_dns configuration")
+	}
+
+	required := dnsCheckNames
+	if !wait.Require.IsNull() && !wait.Require.IsUnknown() {
+		required = nil
+		if diags := wait.Require.ElementsAs(ctx, &required, false); diags.HasError() {
+			return fmt.Errorf("unable to read wait_for_dns.require")
+		}
+	}
+
+	timeout := defaultWaitForDnsTimeout
+	if !wait.Timeout.IsNull() && !wait.Timeout.IsUnknown() {
+		d, err := time.ParseDuration(wait.Timeout.ValueString())
+		if err != nil {
+			return fmt.Errorf("invalid wait_for_dns.timeout: %w", err)
+		}
+		timeout = d
+	}
+
+	interval := defaultWaitForDnsInterval
+	if !wait.Interval.IsNull() && !wait.Interval.IsUnknown() {
+		d, err := time.ParseDuration(wait.Interval.ValueString())
+		if err != nil {
+			return fmt.Errorf("invalid wait_for_dns.interval: %w", err)
+		}
+		interval = d
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if err := r.recheckDns(ctx, data.Name.ValueString()); err != nil {
+			return fmt.Errorf("unable to recheck DNS: %w", err)
+		}
+
+		if err := r.readDomain(ctx, data); err != nil {
+			return fmt.Errorf("unable to read domain: %w", err)
+		}
+
+		var summary DnsSummaryModel
+		if !data.DnsSummary.IsNull() && !data.DnsSummary.IsUnknown() {
+			if diags := data.DnsSummary.As(ctx, &summary, basetypes.ObjectAsOptions{}); diags.HasError() {
+				return fmt.Errorf("unable to read DNS summary")
+			}
+		}
+
+		results := map[string]bool{
+			"mx":    summary.PassesMx.ValueBool(),
+			"spf":   summary.PassesSpf.ValueBool(),
+			"dkim":  summary.PassesDkim.ValueBool(),
+			"dmarc": summary.PassesDmarc.ValueBool(),
+		}
+
+		passed := true
+		for _, name := range required {
+			if !results[name] {
+				passed = false
+			}
+		}
+		if passed {
+			return nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("DNS checks for %s did not pass within %s:\n%s",
+				data.Name.ValueString(), timeout, dnsCheckReport(results, required))
+		}
+
+		tflog.Debug(ctx, "waiting for DNS checks to pass", map[string]interface{}{
+			"domain":   data.Name.ValueString(),
+			"results":  results,
+			"interval": interval.String(),
+		})
+
+		setStep(ctx, "wait for DNS checks")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// recheckDns asks Purelymail to recheck the DNS records of the domain.
+func (r *DomainResource) recheckDns(ctx context.Context, name string) error {
+	setStep(ctx, "recheck DNS")
+	recheckDns := true
+	httpResp, err := r.client.UpdateDomainSettings(ctx, api.UpdateDomainSettingsRequest{
+		Name:       name,
+		RecheckDns: &recheckDns,
+	})
+	if err != nil {
+		return fmt.Errorf("client error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", httpResp.StatusCode)
+	}
+
+	return nil
+}
+
+// dnsCheckReport formats the result of every DNS check, one per line.
+func dnsCheckReport(results map[string]bool, required []string) string {
+	var lines []string
+	for _, name := range dnsCheckNames {
+		status := "passing"
+		if !results[name] {
+			status = "failing"
+		}
+		for _, req := range required {
+			if req == name {
+				status += " (required)"
+				break
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", name, status))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// listDomainDependents describes the users and routing rules that exist on the domain.
+func (r *DomainResource) listDomainDependents(ctx context.Context, name string) ([]string, error) {
+	var dependents []string
+
+	users, err := listUsers(ctx, r.client)
+	if err != nil {
+		return nil, err
+	}
+	for _, userName := range users {
+		if domainName, ok := emailDomain(userName); ok && sameDomainName(domainName, name) {
+			dependents = append(dependents, fmt.Sprintf("user %s", userName))
+		}
+	}
+
+	rules, err := listRoutingRules(ctx, r.client)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.DomainName == nil || !sameDomainName(*rule.DomainName, name) || rule.Id == nil {
+			continue
+		}
+		matchUser := ""
+		if rule.MatchUser != nil {
+			matchUser = *rule.MatchUser
+		}
+		var targets []string
+		if rule.TargetAddresses != nil {
+			targets = *rule.TargetAddresses
+		}
+		dependents = append(dependents, fmt.Sprintf("routing rule %d (%q -> %s)", *rule.Id, matchUser, strings.Join(targets, ", ")))
+	}
+
+	return dependents, nil
+}
+
+// listDomains returns the domains of the account, and the shared Purelymail
+// domains if includeShared is set.
+func listDomains(ctx context.Context, client *api.Client, includeShared bool) ([]api.ApiDomainInfo, error) {
+	httpResp, err := client.ListDomains(ctx, api.ListDomainsRequest{
+		IncludeShared: &includeShared,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list domains: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", httpResp.StatusCode)
+	}
+
+	var listResp api.ListDomainsResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	if listResp.Result == nil {
+		return nil, fmt.Errorf("no result in response")
+	}
+	if listResp.Result.Domains == nil {
+		return nil, nil
+	}
+
+	return *listResp.Result.Domains, nil
+}
+
+// findDomain returns the domain of the account with the given name, or nil if
+// there is none.
+func findDomain(ctx context.Context, client *api.Client, name string) (*api.ApiDomainInfo, error) {
+	domains, err := listDomains(ctx, client, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, domain := range domains {
+		if domain.Name != nil && sameDomainName(*domain.Name, name) {
+			return &domain, nil
+		}
+	}
+
+	return nil, nil
+}
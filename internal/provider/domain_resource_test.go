@@ -1,11 +1,22 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/http/httptest"
+	"regexp"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
@@ -57,6 +68,48 @@ func TestAccDomainResource(t *testing.T) {
 	})
 }
 
+func TestAccDomainResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDomainResourceConfig(ts.URL, "Example.COM.", false, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_domain.test", map[string]knownvalue.Check{
+						"name": knownvalue.StringExact("example.com"),
+					}),
+				},
+			},
+			// Changing only the spelling keeps the identity, and importing
+			// needs the spelling Purelymail returns
+			{
+				Config: testAccDomainResourceConfig(ts.URL, "example.com", false, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_domain.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				ResourceName:    "purelymail_domain.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
 func testAccDomainResourceConfig(endpoint string, domainName string, allowAccountReset bool, symbolicSubaddressing bool) string {
 	return fmt.Sprintf(`
 provider "purelymail" {
@@ -71,3 +124,469 @@ resource "purelymail_domain" "test" {
 }
 `, endpoint, domainName, allowAccountReset, symbolicSubaddressing)
 }
+
+func TestAccDomainResourceNameSpelling(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDomainResourceConfig(ts.URL, "example..com", false, false),
+				ExpectError: regexp.MustCompile(`Invalid Domain Name`),
+			},
+			// The API lowercases the name, the configured spelling is kept
+			{
+				Config: testAccDomainResourceConfig(ts.URL, "Example.COM", false, false),
+				Check:  resource.TestCheckResourceAttr("purelymail_domain.test", "name", "Example.COM"),
+			},
+			// Changing only the spelling updates in place
+			{
+				Config: testAccDomainResourceConfig(ts.URL, "example.com.", false, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_domain.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("purelymail_domain.test", "name", "example.com."),
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func TestAccDomainResourceRecheckTriggers(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDomainResourceConfigRecheckTriggers(ts.URL, "v1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "recheck_triggers.dkim", "v1"),
+					resource.TestCheckResourceAttr("purelymail_domain.test", "dns_summary.passes_dkim", "false"),
+				),
+			},
+			// The records are fixed, but nothing is rechecked until a trigger changes
+			{
+				PreConfig: func() {
+					mockServer.SetDNSState("example.com", mock.DNSState{PassesMx: true, PassesSpf: true, PassesDkim: true, PassesDmarc: true})
+				},
+				Config: testAccDomainResourceConfigRecheckTriggers(ts.URL, "v1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("purelymail_domain.test", "dns_summary.passes_dkim", "false"),
+			},
+			{
+				Config: testAccDomainResourceConfigRecheckTriggers(ts.URL, "v2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_domain.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("purelymail_domain.test", tfjsonpath.New("dns_summary")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "dns_summary.passes_dkim", "true"),
+					resource.TestCheckResourceAttr("purelymail_domain.test", "dns_summary.passes_dmarc", "true"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccDomainResourceConfigRecheckTriggers(endpoint string, dkim string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "test" {
+	name = "example.com"
+
+	recheck_triggers = {
+		dkim = %[2]q
+	}
+}
+`, endpoint, dkim)
+}
+
+func TestDomainResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &DomainResource{}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	cases := []struct {
+		recheckDns string
+		want       tftypes.Value
+	}{
+		// A recheck that was asked for is dropped
+		{recheckDns: "true", want: tftypes.NewValue(tftypes.Bool, nil)},
+		{recheckDns: "false", want: tftypes.NewValue(tftypes.Bool, false)},
+		{recheckDns: "null", want: tftypes.NewValue(tftypes.Bool, nil)},
+	}
+
+	for _, c := range cases {
+		req := fwresource.UpgradeStateRequest{
+			RawState: &tfprotov6.RawState{
+				JSON: []byte(`{"name":"example.com","allow_account_reset":false,"symbolic_subaddressing":true,"recheck_dns":` + c.recheckDns + `,"is_shared":false,"dns_summary":{"passes_mx":true,"passes_spf":true,"passes_dkim":false,"passes_dmarc":false},"id":"example.com"}`),
+			},
+		}
+		var resp fwresource.UpgradeStateResponse
+		r.UpgradeState(ctx)[0].StateUpgrader(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("recheck_dns = %s: unexpected diagnostics: %v", c.recheckDns, resp.Diagnostics)
+		}
+
+		upgraded, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+		if err != nil {
+			t.Fatalf("recheck_dns = %s: upgraded state does not match the current schema: %s", c.recheckDns, err)
+		}
+
+		var attrs map[string]tftypes.Value
+		if err := upgraded.As(&attrs); err != nil {
+			t.Fatal(err)
+		}
+		if !attrs["recheck_triggers"].IsNull() {
+			t.Errorf("recheck_dns = %s: expected recheck_triggers to be null, got %s", c.recheckDns, attrs["recheck_triggers"])
+		}
+		if !attrs["recheck_dns"].Equal(c.want) {
+			t.Errorf("recheck_dns = %s: expected recheck_dns %s, got %s", c.recheckDns, c.want, attrs["recheck_dns"])
+		}
+		var name string
+		if err := attrs["name"].As(&name); err != nil || name != "example.com" {
+			t.Errorf("recheck_dns = %s: expected name to be kept, got %s", c.recheckDns, attrs["name"])
+		}
+	}
+}
+
+func TestAccDomainResourceWaitForDns(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// DKIM never propagates, so the wait times out with a report
+			{
+				PreConfig: func() {
+					mockServer.SetDNSState("example.com", mock.DNSState{PassesMx: true, PassesSpf: true})
+				},
+				Config:      testAccDomainResourceConfigWaitForDns(ts.URL, "1s"),
+				ExpectError: regexp.MustCompile(`dkim: failing \(required\)`),
+			},
+			// The domain is kept in state but tainted, so the next apply adds it
+			// again. DKIM propagates while polling.
+			{
+				PreConfig: func() {
+					time.AfterFunc(time.Second, func() {
+						mockServer.SetDNSState("example.com", mock.DNSState{PassesMx: true, PassesSpf: true, PassesDkim: true})
+					})
+				},
+				Config: testAccDomainResourceConfigWaitForDns(ts.URL, "30s"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_domain.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "dns_summary.passes_dkim", "true"),
+					resource.TestCheckResourceAttr("purelymail_domain.test", "dns_summary.passes_dmarc", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccDomainResourceConfigWaitForDns(endpoint string, timeout string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "test" {
+	name = "example.com"
+
+	wait_for_dns {
+		require  = ["mx", "spf", "dkim"]
+		timeout  = %[2]q
+		interval = "200ms"
+	}
+}
+`, endpoint, timeout)
+}
+
+func TestAccDomainResourceTimeouts(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The create timeout ends the wait for DKIM before wait_for_dns does
+			{
+				PreConfig: func() {
+					mockServer.SetDNSState("example.com", mock.DNSState{PassesMx: true, PassesSpf: true})
+				},
+				Config:      testAccDomainResourceConfigTimeouts(ts.URL, "1s"),
+				ExpectError: regexp.MustCompile(`create\s+operation\s+did\s+not\s+finish\s+within\s+1s,\s+it\s+timed\s+out\s+during\s+step\s+"wait\s+for\s+DNS\s+checks"`),
+			},
+			// A stuck API call is bounded as well, replacing the tainted domain
+			{
+				PreConfig: func() {
+					mockServer.SetDelay("AddDomain", time.Minute)
+				},
+				Config:      testAccDomainResourceConfigTimeouts(ts.URL, "1s"),
+				ExpectError: regexp.MustCompile(`step\s+"add\s+domain"`),
+			},
+			{
+				PreConfig: func() {
+					mockServer.SetDelay("AddDomain", 0)
+					mockServer.SetDNSState("example.com", mock.DNSState{PassesMx: true, PassesSpf: true, PassesDkim: true})
+				},
+				Config: testAccDomainResourceConfigTimeouts(ts.URL, "1m"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_domain.test", tfjsonpath.New("timeouts").AtMapKey("create"), knownvalue.StringExact("1m")),
+					statecheck.ExpectKnownValue("purelymail_domain.test", tfjsonpath.New("dns_summary").AtMapKey("passes_dkim"), knownvalue.Bool(true)),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccDomainResourceConfigTimeouts(endpoint string, createTimeout string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "test" {
+	name = "example.com"
+
+	wait_for_dns {
+		require  = ["mx", "spf", "dkim"]
+		timeout  = "1m"
+		interval = "200ms"
+	}
+
+	timeouts {
+		create = %[2]q
+	}
+}
+`, endpoint, createTimeout)
+}
+
+func TestAccDomainResourcePreflightDns(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Stand-in DNS server for the preflight check
+	dnsServer, err := mock.NewDNSServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dnsServer.Close()
+	dnsServer.SetMX("example.com", "mailserver.purelymail.com")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Ownership proof is not published yet
+			{
+				Config:      testAccDomainResourceConfigPreflightDns(ts.URL, dnsServer.Addr()),
+				ExpectError: regexp.MustCompile(`TXT example.com: missing ownership proof "mock-ownership-code-123"`),
+			},
+			// Ownership proof is visible
+			{
+				PreConfig: func() {
+					dnsServer.SetTXT("example.com", "v=spf1 include:_spf.purelymail.com ~all", "mock-ownership-code-123")
+				},
+				Config: testAccDomainResourceConfigPreflightDns(ts.URL, dnsServer.Addr()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "name", "example.com"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccDomainResourceConfigPreflightDns(endpoint string, resolver string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "test" {
+	name               = "example.com"
+	preflight_dns      = true
+	preflight_resolver = %[2]q
+	preflight_timeout  = "1s"
+}
+`, endpoint, resolver)
+}
+
+func TestAccDomainResourceDeletionGuards(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a protected domain
+			{
+				Config: testAccDomainResourceConfigDeletionGuards(ts.URL, true, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "deletion_protection", "true"),
+					resource.TestCheckResourceAttr("purelymail_domain.test", "force_destroy", "false"),
+				),
+			},
+			// Destroying a protected domain fails
+			{
+				Config:      testAccDomainResourceConfigDeletionGuards(ts.URL, true, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Deletion Protection Enabled"),
+			},
+			// Lift the protection
+			{
+				Config: testAccDomainResourceConfigDeletionGuards(ts.URL, false, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "deletion_protection", "false"),
+				),
+			},
+			// Destroying a domain with users fails and lists them
+			{
+				PreConfig: func() {
+					client, err := api.NewClient(ts.URL)
+					if err != nil {
+						t.Fatal(err)
+					}
+					httpResp, err := client.CreateUser(context.Background(), api.CreateUserRequest{UserName: "alice@example.com"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					httpResp.Body.Close()
+				},
+				Config:      testAccDomainResourceConfigDeletionGuards(ts.URL, false, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("user alice@example.com"),
+			},
+			// force_destroy allows deleting the domain with its users
+			{
+				Config: testAccDomainResourceConfigDeletionGuards(ts.URL, false, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "force_destroy", "true"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccDomainResourceConfigDeletionGuards(endpoint string, deletionProtection bool, forceDestroy bool) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "test" {
+	name                = "example.com"
+	deletion_protection = %[2]t
+	force_destroy       = %[3]t
+}
+`, endpoint, deletionProtection, forceDestroy)
+}
+
+func TestAccDomainResourceAdoptExisting(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Seed a domain that was added outside of Terraform
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp, err := client.AddDomain(context.Background(), api.AddDomainRequest{DomainName: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Adding a domain that is already on the account fails
+			{
+				Config:      testAccDomainResourceConfigAdoptExisting(ts.URL, false),
+				ExpectError: regexp.MustCompile("domain already exists"),
+			},
+			// Adopting reconciles the existing domain to the configuration
+			{
+				Config: testAccDomainResourceConfigAdoptExisting(ts.URL, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_domain.test", "id", "example.com"),
+					resource.TestCheckResourceAttr("purelymail_domain.test", "symbolic_subaddressing", "true"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccDomainResourceConfigAdoptExisting(endpoint string, adoptExisting bool) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_domain" "test" {
+	name                   = "example.com"
+	symbolic_subaddressing = true
+	adopt_existing         = %[2]t
+}
+`, endpoint, adoptExisting)
+}
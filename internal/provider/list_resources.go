@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"iter"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// limitListResults stops a stream of list results after limit results, the
+// number Terraform asked for, so no API calls are made for results Terraform
+// would drop. Results that only carry diagnostics don't count, and a limit of
+// zero or less means no limit.
+func limitListResults(limit int64, results iter.Seq[list.ListResult]) iter.Seq[list.ListResult] {
+	return func(push func(list.ListResult) bool) {
+		var count int64
+		for result := range results {
+			if !push(result) {
+				return
+			}
+			if result.Identity != nil {
+				count++
+			}
+			if limit > 0 && count >= limit {
+				return
+			}
+		}
+	}
+}
+
+// listResultError returns a list result that only reports an error.
+func listResultError(summary string, detail string) list.ListResult {
+	var diags diag.Diagnostics
+	diags.AddError(summary, detail)
+
+	return list.ListResult{Diagnostics: diags}
+}
+
+// importedResource returns the model of a listed resource as it is right after
+// an import, with only the attribute at key set to value and all others null.
+func importedResource[T any](ctx context.Context, resource *tfsdk.Resource, key path.Path, value any) (T, diag.Diagnostics) {
+	var data T
+	var diags diag.Diagnostics
+
+	diags.Append(resource.SetAttribute(ctx, key, value)...)
+	if diags.HasError() {
+		return data, diags
+	}
+	diags.Append(resource.Get(ctx, &data)...)
+
+	return data, diags
+}
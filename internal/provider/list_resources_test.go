@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+func TestLimitListResults(t *testing.T) {
+	cases := []struct {
+		limit    int64
+		produced int
+		pushed   int
+	}{
+		{limit: 0, produced: 5, pushed: 5},
+		{limit: 2, produced: 2, pushed: 2},
+		{limit: 10, produced: 5, pushed: 5},
+	}
+
+	for _, c := range cases {
+		produced := 0
+		results := func(push func(list.ListResult) bool) {
+			for range 5 {
+				produced++
+				if !push(list.ListResult{Identity: &tfsdk.ResourceIdentity{}}) {
+					return
+				}
+			}
+		}
+
+		pushed := 0
+		for range limitListResults(c.limit, results) {
+			pushed++
+		}
+
+		if produced != c.produced || pushed != c.pushed {
+			t.Errorf("limit %d: got %d produced and %d pushed results, want %d and %d", c.limit, produced, pushed, c.produced, c.pushed)
+		}
+	}
+}
+
+func TestLimitListResultsDiagnostics(t *testing.T) {
+	results := func(push func(list.ListResult) bool) {
+		if !push(listResultError("Client Error", "first")) {
+			return
+		}
+		if !push(list.ListResult{Identity: &tfsdk.ResourceIdentity{}}) {
+			return
+		}
+		push(list.ListResult{Identity: &tfsdk.ResourceIdentity{}})
+	}
+
+	var got []list.ListResult
+	for result := range limitListResults(1, results) {
+		got = append(got, result)
+	}
+
+	// Results that only carry diagnostics don't count towards the limit
+	if len(got) != 2 || !got[0].Diagnostics.HasError() || got[1].Identity == nil {
+		t.Errorf("expected the error and one result, got %d results", len(got))
+	}
+}
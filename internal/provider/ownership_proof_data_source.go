@@ -73,31 +73,38 @@ func (d *OwnershipProofDataSource) Configure(ctx context.Context, req datasource
 func (d *OwnershipProofDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state OwnershipProofDataSourceModel
 
-	httpResp, err := d.client.GetOwnershipCode(ctx, map[string]interface{}{})
+	code, err := getOwnershipCode(ctx, d.client)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get ownership code: %s", err))
 		return
 	}
+
+	state.Code = types.StringValue(code)
+	state.Id = types.StringValue(code)
+
+	tflog.Trace(ctx, "read purelymail_ownership_proof data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// getOwnershipCode returns the value of the ownership proof TXT record.
+func getOwnershipCode(ctx context.Context, client *api.Client) (string, error) {
+	httpResp, err := client.GetOwnershipCode(ctx, map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
 	defer httpResp.Body.Close()
 	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unexpected status code: %d", httpResp.StatusCode))
-		return
+		return "", fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
 	}
 
 	var decoded api.GetOwnershipCodeResponse
 	if err := json.NewDecoder(httpResp.Body).Decode(&decoded); err != nil {
-		resp.Diagnostics.AddError("Decode Error", fmt.Sprintf("Unable to decode response: %s", err))
-		return
+		return "", fmt.Errorf("unable to decode response: %w", err)
 	}
 	if decoded.Result == nil || decoded.Result.Code == nil {
-		resp.Diagnostics.AddError("Response Error", "Missing ownership code in response")
-		return
+		return "", fmt.Errorf("missing ownership code in response")
 	}
 
-	state.Code = types.StringValue(*decoded.Result.Code)
-	state.Id = types.StringValue(*decoded.Result.Code)
-
-	tflog.Trace(ctx, "read purelymail_ownership_proof data source")
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	return *decoded.Result.Code, nil
 }
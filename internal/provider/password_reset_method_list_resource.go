@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &PasswordResetMethodListResource{}
+var _ list.ListResourceWithConfigure = &PasswordResetMethodListResource{}
+
+func NewPasswordResetMethodListResource() list.ListResource {
+	return &PasswordResetMethodListResource{}
+}
+
+// PasswordResetMethodListResource defines the list resource implementation.
+type PasswordResetMethodListResource struct {
+	client *api.Client
+}
+
+// PasswordResetMethodListResourceModel describes the list resource config
+// model.
+type PasswordResetMethodListResourceModel struct {
+	UserName   MailboxNameValue `tfsdk:"user_name"`
+	DomainName DomainNameValue  `tfsdk:"domain_name"`
+	Type       types.String     `tfsdk:"type"`
+}
+
+func (r *PasswordResetMethodListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_password_reset_method"
+}
+
+func (r *PasswordResetMethodListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the password reset methods of the users of the account. " +
+			"Without `user_name`, the methods of every user are listed, which takes one API call per user.",
+
+		Attributes: map[string]schema.Attribute{
+			"user_name": schema.StringAttribute{
+				MarkdownDescription: "Only list the password reset methods of this user.",
+				CustomType:          MailboxNameType{},
+				Optional:            true,
+			},
+			"domain_name": schema.StringAttribute{
+				MarkdownDescription: "Only list the password reset methods of users of this domain.",
+				CustomType:          DomainNameType{},
+				Optional:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Only list password reset methods of this type, `email` or `phone`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(passwordResetMethodTypes...),
+				},
+			},
+		},
+	}
+}
+
+func (r *PasswordResetMethodListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *PasswordResetMethodListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config PasswordResetMethodListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	// Purelymail spells the domain of user names in lowercase
+	userNames := []string{normalizedOrRaw(normalizeMailboxName, config.UserName.ValueString())}
+	if config.UserName.IsNull() {
+		var err error
+		userNames, err = listUsers(ctx, r.client)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to list users: %s", err))
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	stream.Results = limitListResults(req.Limit, func(push func(list.ListResult) bool) {
+		for _, userName := range userNames {
+			if !config.DomainName.IsNull() {
+				domainName, ok := emailDomain(userName)
+				if !ok || !sameDomainName(domainName, config.DomainName.ValueString()) {
+					continue
+				}
+			}
+
+			methods, err := listPasswordResetMethods(ctx, r.client, userName)
+			if err != nil {
+				push(listResultError("Client Error", fmt.Sprintf("Unable to list password reset methods of %s: %s", userName, err)))
+				return
+			}
+
+			for _, method := range methods {
+				if !config.Type.IsNull() && method.Type.ValueString() != config.Type.ValueString() {
+					continue
+				}
+
+				result := r.listResult(ctx, req, userName, method)
+				if !push(result) || result.Diagnostics.HasError() {
+					return
+				}
+			}
+		}
+	})
+}
+
+// listResult returns the list result of a password reset method of a user.
+func (r *PasswordResetMethodListResource) listResult(ctx context.Context, req list.ListRequest, userName string, method PasswordResetMethodModel) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = fmt.Sprintf("%s: %s", userName, method.Target)
+
+	data := PasswordResetMethodResourceModel{
+		UserName: NewMailboxNameValue(userName),
+		Target:   NewPasswordResetTargetValue(method.Target),
+	}
+	if req.IncludeResource {
+		var diags diag.Diagnostics
+		data, diags = importedResource[PasswordResetMethodResourceModel](ctx, result.Resource, path.Root("user_name"), userName)
+		result.Diagnostics.Append(diags...)
+		if result.Diagnostics.HasError() {
+			return result
+		}
+
+		data.Id = types.StringValue(userName + ":" + method.Target)
+		data.Target = NewPasswordResetTargetValue(method.Target)
+		data.Type = method.Type
+		data.Description = method.Description
+		data.AllowMfaReset = method.AllowMfaReset
+		data.AdoptExisting = types.BoolValue(false)
+
+		result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+	}
+
+	result.Diagnostics.Append(result.Identity.Set(ctx, passwordResetMethodIdentity(&data))...)
+
+	return result
+}
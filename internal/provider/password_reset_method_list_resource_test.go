@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestAccPasswordResetMethodListResource(t *testing.T) {
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	phoneIdentity := queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+		"user_name": knownvalue.StringExact("alice@example.com"),
+		"target":    knownvalue.StringExact("+15555550100"),
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordResetMethodListResourceMethodsConfig(ts.URL),
+			},
+			// Methods of all users
+			{
+				Query:  true,
+				Config: testAccPasswordResetMethodListResourceConfig("", false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_password_reset_method.test", 3),
+					querycheck.ExpectIdentity("purelymail_password_reset_method.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("bob@example.org"),
+						"target":    knownvalue.StringExact("bob@recovery.example"),
+					}),
+				},
+			},
+			{
+				Query:  true,
+				Config: testAccPasswordResetMethodListResourceConfig(`user_name = "alice@EXAMPLE.com"`, true),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_password_reset_method.test", 2),
+					querycheck.ExpectResourceDisplayName("purelymail_password_reset_method.test", phoneIdentity,
+						knownvalue.StringExact("alice@example.com: +15555550100")),
+					querycheck.ExpectResourceKnownValues("purelymail_password_reset_method.test", phoneIdentity, []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("id"),
+							KnownValue: knownvalue.StringExact("alice@example.com:+15555550100"),
+						},
+						{
+							Path:       tfjsonpath.New("type"),
+							KnownValue: knownvalue.StringExact("phone"),
+						},
+						{
+							Path:       tfjsonpath.New("description"),
+							KnownValue: knownvalue.StringExact("Alice's phone"),
+						},
+						{
+							Path:       tfjsonpath.New("allow_mfa_reset"),
+							KnownValue: knownvalue.Bool(true),
+						},
+					}),
+				},
+			},
+			{
+				Query:  true,
+				Config: testAccPasswordResetMethodListResourceConfig(`domain_name = "example.com"`+"\n"+`type = "email"`, false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_password_reset_method.test", 1),
+					querycheck.ExpectIdentity("purelymail_password_reset_method.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("alice@example.com"),
+						"target":    knownvalue.StringExact("alice@recovery.example"),
+					}),
+				},
+			},
+		},
+	})
+}
+
+func testAccPasswordResetMethodListResourceMethodsConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_user" "alice" {
+	user_name                   = "alice@example.com"
+	password                    = "alice-password"
+	password_reset_methods_mode = "ignore"
+}
+
+resource "purelymail_user" "bob" {
+	user_name                   = "bob@example.org"
+	password                    = "bob-password"
+	password_reset_methods_mode = "ignore"
+}
+
+resource "purelymail_password_reset_method" "alice_email" {
+	user_name = purelymail_user.alice.user_name
+	type      = "email"
+	target    = "alice@recovery.example"
+}
+
+resource "purelymail_password_reset_method" "alice_phone" {
+	user_name       = purelymail_user.alice.user_name
+	type            = "phone"
+	target          = "+15555550100"
+	description     = "Alice's phone"
+	allow_mfa_reset = true
+}
+
+resource "purelymail_password_reset_method" "bob_email" {
+	user_name = purelymail_user.bob.user_name
+	type      = "email"
+	target    = "bob@recovery.example"
+}
+`, endpoint)
+}
+
+// The query configs have no provider block, the one of the resources config
+// is used.
+func testAccPasswordResetMethodListResourceConfig(filter string, includeResource bool) string {
+	return fmt.Sprintf(`
+list "purelymail_password_reset_method" "test" {
+	provider         = purelymail
+	include_resource = %[2]t
+
+	config {
+		%[1]s
+	}
+}
+`, filter, includeResource)
+}
@@ -7,12 +7,16 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -22,6 +26,10 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PasswordResetMethodResource{}
 var _ resource.ResourceWithImportState = &PasswordResetMethodResource{}
+var _ resource.ResourceWithModifyPlan = &PasswordResetMethodResource{}
+var _ resource.ResourceWithValidateConfig = &PasswordResetMethodResource{}
+var _ resource.ResourceWithIdentity = &PasswordResetMethodResource{}
+var _ resource.ResourceWithMoveState = &PasswordResetMethodResource{}
 
 func NewPasswordResetMethodResource() resource.Resource {
 	return &PasswordResetMethodResource{}
@@ -34,18 +42,50 @@ type PasswordResetMethodResource struct {
 
 // PasswordResetMethodResourceModel describes the resource data model.
 type PasswordResetMethodResourceModel struct {
-	UserName      types.String `tfsdk:"user_name"`
-	Type          types.String `tfsdk:"type"`
-	Target        types.String `tfsdk:"target"`
-	Description   types.String `tfsdk:"description"`
-	AllowMfaReset types.Bool   `tfsdk:"allow_mfa_reset"`
-	Id            types.String `tfsdk:"id"`
+	UserName      MailboxNameValue         `tfsdk:"user_name"`
+	Type          types.String             `tfsdk:"type"`
+	Target        PasswordResetTargetValue `tfsdk:"target"`
+	Description   types.String             `tfsdk:"description"`
+	AllowMfaReset types.Bool               `tfsdk:"allow_mfa_reset"`
+	AdoptExisting types.Bool               `tfsdk:"adopt_existing"`
+	Id            types.String             `tfsdk:"id"`
+	Timeouts      timeouts.Value           `tfsdk:"timeouts"`
+}
+
+// PasswordResetMethodResourceIdentityModel describes the resource identity.
+type PasswordResetMethodResourceIdentityModel struct {
+	UserName types.String `tfsdk:"user_name"`
+	Target   types.String `tfsdk:"target"`
+}
+
+// passwordResetMethodIdentity returns the identity of a password reset method,
+// with the user name and target normalized.
+func passwordResetMethodIdentity(data *PasswordResetMethodResourceModel) PasswordResetMethodResourceIdentityModel {
+	return PasswordResetMethodResourceIdentityModel{
+		UserName: types.StringValue(normalizedOrRaw(normalizeMailboxName, data.UserName.ValueString())),
+		Target:   types.StringValue(normalizedOrRaw(normalizeAnyPasswordResetTarget, data.Target.ValueString())),
+	}
 }
 
 func (r *PasswordResetMethodResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_password_reset_method"
 }
 
+func (r *PasswordResetMethodResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"user_name": identityschema.StringAttribute{
+				Description:       "The username the password reset method belongs to.",
+				RequiredForImport: true,
+			},
+			"target": identityschema.StringAttribute{
+				Description:       "The email address or phone number of the password reset method.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *PasswordResetMethodResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a password reset method for a Purelymail user. At least one password reset method must exist before enabling two-factor authentication on the user.",
@@ -53,20 +93,25 @@ func (r *PasswordResetMethodResource) Schema(ctx context.Context, req resource.S
 		Attributes: map[string]schema.Attribute{
 			"user_name": schema.StringAttribute{
 				MarkdownDescription: "The full email address this password reset method belongs to (e.g., 'alice@example.com').",
+				CustomType:          MailboxNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessSemanticallyEqual(MailboxNameType{}),
 				},
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "The type of password reset method (e.g., 'email' or 'phone').",
+				MarkdownDescription: "The type of password reset method. Valid values: 'email', 'phone'.",
 				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(passwordResetMethodTypes...),
+				},
 			},
 			"target": schema.StringAttribute{
-				MarkdownDescription: "The target for the password reset method (email address or phone number).",
+				MarkdownDescription: "The target for the password reset method: an email address, or a phone number in international format like '+15555550100'. Phone numbers are sent in E.164 format.",
+				CustomType:          PasswordResetTargetType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessSemanticallyEqual(PasswordResetTargetType{}),
 				},
 			},
 			"description": schema.StringAttribute{
@@ -79,6 +124,12 @@ func (r *PasswordResetMethodResource) Schema(ctx context.Context, req resource.S
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
 			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to take over a password reset method with the same target that already exists on the user instead of failing. The existing method is changed to match the configuration.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The identifier for this password reset method (format: username:target).",
 				Computed:            true,
@@ -87,6 +138,9 @@ func (r *PasswordResetMethodResource) Schema(ctx context.Context, req resource.S
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
 	}
 }
 
@@ -115,11 +169,42 @@ func (r *PasswordResetMethodResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	// The API upserts by target, so check first to avoid silently overwriting a method
+	existing := data
+	found, err := r.readPasswordResetMethod(ctx, &existing)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for existing password reset method: %s", err))
+		return
+	}
+	if found {
+		if !data.AdoptExisting.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("target"),
+				"Password Reset Method Already Exists",
+				fmt.Sprintf("User %s already has a password reset method for %s. Import it with '%s:%s', or set adopt_existing = true to take it over.",
+					data.UserName.ValueString(), data.Target.ValueString(), data.UserName.ValueString(), data.Target.ValueString()),
+			)
+			return
+		}
+
+		resp.Diagnostics.AddWarning(
+			"Existing Password Reset Method Adopted",
+			fmt.Sprintf("User %s already had a password reset method for %s and it was adopted. It was changed to match the configuration.", data.UserName.ValueString(), data.Target.ValueString()),
+		)
+	}
+
 	// Create password reset method via API
 	upsertReq := api.UpsertPasswordResetRequest{
 		UserName: data.UserName.ValueString(),
-		Type:     data.Type.ValueString(),
-		Target:   data.Target.ValueString(),
+		Type:     api.UserPasswordResetMethodType(data.Type.ValueString()),
+		Target:   apiPasswordResetTarget(data.Type.ValueString(), data.Target.ValueString()),
 	}
 
 	if !data.Description.IsNull() && !data.Description.IsUnknown() {
@@ -132,6 +217,7 @@ func (r *PasswordResetMethodResource) Create(ctx context.Context, req resource.C
 		upsertReq.AllowMfaReset = &allow
 	}
 
+	setStep(ctx, "upsert password reset method")
 	httpResp, err := r.client.CreateOrUpdatePasswordResetMethod(ctx, upsertReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create password reset method: %s", err))
@@ -155,6 +241,7 @@ func (r *PasswordResetMethodResource) Create(ctx context.Context, req resource.C
 	tflog.Trace(ctx, "created purelymail_password_reset_method resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, passwordResetMethodIdentity(&data))...)
 }
 
 func (r *PasswordResetMethodResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -165,6 +252,13 @@ func (r *PasswordResetMethodResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "read", data.Timeouts.Read, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	found, err := r.readPasswordResetMethod(ctx, &data)
 	if err != nil {
 		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read password reset method: %s", err))
@@ -177,9 +271,15 @@ func (r *PasswordResetMethodResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
+	// Imported resources have no adopt_existing value yet
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+
 	tflog.Trace(ctx, "read purelymail_password_reset_method resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, passwordResetMethodIdentity(&data))...)
 }
 
 func (r *PasswordResetMethodResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -190,23 +290,27 @@ func (r *PasswordResetMethodResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "update", data.Timeouts.Update, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	var state PasswordResetMethodResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	keepPriorSpelling(&data.UserName, state.UserName)
+	keepPriorSpelling(&data.Target, state.Target)
+
 	// Update via upsert API
 	upsertReq := api.UpsertPasswordResetRequest{
 		UserName: data.UserName.ValueString(),
-		Type:     data.Type.ValueString(),
-		Target:   data.Target.ValueString(),
-	}
-
-	// Include existing target if it changed (for update operation)
-	if state.Target.ValueString() != data.Target.ValueString() {
-		existing := state.Target.ValueString()
-		upsertReq.ExistingTarget = &existing
+		Type:     api.UserPasswordResetMethodType(data.Type.ValueString()),
+		Target:   apiPasswordResetTarget(data.Type.ValueString(), data.Target.ValueString()),
 	}
 
 	if !data.Description.IsNull() && !data.Description.IsUnknown() {
@@ -219,6 +323,7 @@ func (r *PasswordResetMethodResource) Update(ctx context.Context, req resource.U
 		upsertReq.AllowMfaReset = &allow
 	}
 
+	setStep(ctx, "upsert password reset method")
 	httpResp, err := r.client.CreateOrUpdatePasswordResetMethod(ctx, upsertReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update password reset method: %s", err))
@@ -231,7 +336,6 @@ func (r *PasswordResetMethodResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
-	// Update ID if target changed
 	data.Id = types.StringValue(fmt.Sprintf("%s:%s", data.UserName.ValueString(), data.Target.ValueString()))
 
 	// Read back to get current state
@@ -242,6 +346,7 @@ func (r *PasswordResetMethodResource) Update(ctx context.Context, req resource.U
 	tflog.Trace(ctx, "updated purelymail_password_reset_method resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, passwordResetMethodIdentity(&data))...)
 }
 
 func (r *PasswordResetMethodResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -252,9 +357,17 @@ func (r *PasswordResetMethodResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	setStep(ctx, "delete password reset method")
 	httpResp, err := r.client.DeletePasswordResetMethod(ctx, api.DeletePasswordResetRequest{
 		UserName: data.UserName.ValueString(),
-		Target:   data.Target.ValueString(),
+		Target:   apiPasswordResetTarget(data.Type.ValueString(), data.Target.ValueString()),
 	})
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete password reset method: %s", err))
@@ -270,7 +383,74 @@ func (r *PasswordResetMethodResource) Delete(ctx context.Context, req resource.D
 	tflog.Trace(ctx, "deleted purelymail_password_reset_method resource")
 }
 
+func (r *PasswordResetMethodResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data PasswordResetMethodResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validatePasswordResetTarget(path.Root("target"), data.Type, data.Target)...)
+}
+
+func (r *PasswordResetMethodResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state PasswordResetMethodResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.UserName.Equal(state.UserName) {
+		resp.Diagnostics.Append(warnUnknownUserDomain(ctx, r.client, path.Root("user_name"), plan.UserName.StringValue)...)
+	}
+
+	// Only creates can adopt an existing method
+	if !req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	if !plan.AdoptExisting.ValueBool() || plan.UserName.IsUnknown() || plan.Target.IsUnknown() {
+		return
+	}
+
+	existing := plan
+	found, err := r.readPasswordResetMethod(ctx, &existing)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for existing password reset method: %s", err))
+		return
+	}
+	if found {
+		resp.Diagnostics.AddWarning(
+			"Existing Password Reset Method Will Be Adopted",
+			fmt.Sprintf("User %s already has a password reset method for %s. It will be adopted instead of created, and changed to match the configuration.", plan.UserName.ValueString(), plan.Target.ValueString()),
+		)
+	}
+}
+
 func (r *PasswordResetMethodResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" {
+		// Import by identity
+		var identity PasswordResetMethodResourceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.UserName.ValueString()+":"+identity.Target.ValueString())...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_name"), identity.UserName.ValueString())...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target"), identity.Target.ValueString())...)
+		return
+	}
+
 	// Import format: "username:target"
 	idParts := strings.Split(req.ID, ":")
 	if len(idParts) != 2 {
@@ -287,9 +467,94 @@ func (r *PasswordResetMethodResource) ImportState(ctx context.Context, req resou
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target"), idParts[1])...)
 }
 
+func (r *PasswordResetMethodResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: r.moveFromUser,
+		},
+	}
+}
+
+// moveFromUser moves the password reset method of a purelymail_user to a
+// purelymail_password_reset_method. A moved block moves one resource to one
+// resource, so the user must have exactly one password reset method.
+func (r *PasswordResetMethodResource) moveFromUser(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if req.SourceTypeName != "purelymail_user" || !strings.HasSuffix(req.SourceProviderAddress, "/purelymail") {
+		return
+	}
+
+	var prior map[string]json.RawMessage
+	if err := json.Unmarshal(req.SourceRawState.JSON, &prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to decode purelymail_user state: %s", err))
+		return
+	}
+
+	// States before version 2 stored the methods as a list
+	if req.SourceSchemaVersion < 2 {
+		if err := upgradeUserPasswordResetMethods(prior); err != nil {
+			resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to upgrade purelymail_user state: %s", err))
+			return
+		}
+	}
+
+	var userName string
+	var methods map[string]struct {
+		Type          string  `json:"type"`
+		Description   *string `json:"description"`
+		AllowMfaReset *bool   `json:"allow_mfa_reset"`
+	}
+	if err := json.Unmarshal(prior["user_name"], &userName); err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to decode user_name of purelymail_user state: %s", err))
+		return
+	}
+	if err := json.Unmarshal(prior["password_reset_methods"], &methods); len(prior["password_reset_methods"]) > 0 && err != nil {
+		resp.Diagnostics.AddError("Unable to Move State", fmt.Sprintf("Unable to decode password_reset_methods of purelymail_user state: %s", err))
+		return
+	}
+
+	if len(methods) != 1 {
+		resp.Diagnostics.AddError(
+			"Unable to Move State",
+			fmt.Sprintf("User %s has %d password reset methods in state, but only a user with exactly one can be moved to a purelymail_password_reset_method. "+
+				"Import the methods as purelymail_password_reset_method resources instead.", userName, len(methods)),
+		)
+		return
+	}
+
+	data := PasswordResetMethodResourceModel{
+		UserName:      NewMailboxNameValue(userName),
+		AllowMfaReset: types.BoolValue(false),
+		AdoptExisting: types.BoolValue(false),
+	}
+	for target, method := range methods {
+		data.Type = types.StringValue(method.Type)
+		data.Target = NewPasswordResetTargetValue(target)
+		data.Description = types.StringPointerValue(method.Description)
+		if method.AllowMfaReset != nil {
+			data.AllowMfaReset = types.BoolValue(*method.AllowMfaReset)
+		}
+	}
+	data.Id = types.StringValue(fmt.Sprintf("%s:%s", data.UserName.ValueString(), data.Target.ValueString()))
+
+	// Attributes that aren't set, like timeouts, stay null
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("user_name"), data.UserName)...)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("type"), data.Type)...)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("target"), data.Target)...)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("description"), data.Description)...)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("allow_mfa_reset"), data.AllowMfaReset)...)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("adopt_existing"), data.AdoptExisting)...)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("id"), data.Id)...)
+	if resp.TargetIdentity != nil {
+		resp.Diagnostics.Append(resp.TargetIdentity.Set(ctx, passwordResetMethodIdentity(&data))...)
+	}
+
+	tflog.Trace(ctx, "moved purelymail_user password reset method to purelymail_password_reset_method")
+}
+
 // readPasswordResetMethod reads the password reset method from the API
 // Returns true if found, false if not found (but no error).
 func (r *PasswordResetMethodResource) readPasswordResetMethod(ctx context.Context, data *PasswordResetMethodResourceModel) (bool, error) {
+	setStep(ctx, "read password reset method")
 	httpResp, err := r.client.ListPasswordResetMethods(ctx, api.ListPasswordResetRequest{
 		UserName: data.UserName.ValueString(),
 	})
@@ -314,10 +579,10 @@ func (r *PasswordResetMethodResource) readPasswordResetMethod(ctx context.Contex
 	// Find the matching method by target
 	targetValue := data.Target.ValueString()
 	for _, method := range *listResp.Result.Users {
-		if method.Target != nil && *method.Target == targetValue {
+		if method.Target != nil && samePasswordResetTarget(*method.Target, targetValue) {
 			// Update data from API response
 			if method.Type != nil {
-				data.Type = types.StringValue(*method.Type)
+				data.Type = types.StringValue(string(*method.Type))
 			}
 			if method.Description != nil {
 				data.Description = types.StringValue(*method.Description)
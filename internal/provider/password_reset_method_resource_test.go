@@ -1,11 +1,21 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http/httptest"
+	"regexp"
+	"slices"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
@@ -52,6 +62,49 @@ func TestAccPasswordResetMethodResource(t *testing.T) {
 	})
 }
 
+func TestAccPasswordResetMethodResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordResetMethodResourceConfig(ts.URL, "alice@Example.com", "phone", "+1 (555) 555-0100"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_password_reset_method.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("alice@example.com"),
+						"target":    knownvalue.StringExact("+15555550100"),
+					}),
+				},
+			},
+			// Changing only the spelling keeps the identity, and importing
+			// needs the spelling Purelymail returns
+			{
+				Config: testAccPasswordResetMethodResourceConfig(ts.URL, "alice@example.com", "phone", "+15555550100"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_password_reset_method.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				ResourceName:    "purelymail_password_reset_method.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
 func testAccPasswordResetMethodResourceConfig(endpoint string, userName string, methodType string, target string) string {
 	return fmt.Sprintf(`
 provider "purelymail" {
@@ -83,3 +136,243 @@ resource "purelymail_password_reset_method" "test" {
 }
 `, endpoint, userName, methodType, target, description, allowMfaReset)
 }
+
+func TestAccPasswordResetMethodResourceAdoptExisting(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Seed a method that was created outside of Terraform
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp, err := client.CreateOrUpdatePasswordResetMethod(context.Background(), api.UpsertPasswordResetRequest{
+		UserName: "alice",
+		Type:     "email",
+		Target:   "alice@recovery.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Creating a method that already exists fails instead of overwriting it
+			{
+				Config:      testAccPasswordResetMethodResourceConfigAdoptExisting(ts.URL, false),
+				ExpectError: regexp.MustCompile("Password Reset Method Already Exists"),
+			},
+			// Adopting updates the existing method
+			{
+				Config: testAccPasswordResetMethodResourceConfigAdoptExisting(ts.URL, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_password_reset_method.test", "id", "alice:alice@recovery.example.com"),
+					resource.TestCheckResourceAttr("purelymail_password_reset_method.test", "description", "Adopted"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccPasswordResetMethodResourceConfigAdoptExisting(endpoint string, adoptExisting bool) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_password_reset_method" "test" {
+  user_name      = "alice"
+  type           = "email"
+  target         = "alice@recovery.example.com"
+  description    = "Adopted"
+  adopt_existing = %[2]t
+}
+`, endpoint, adoptExisting)
+}
+
+func TestAccPasswordResetMethodResourcePhone(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPasswordResetMethodResourceConfig(ts.URL, "alice", "sms", "+15555550100"),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      testAccPasswordResetMethodResourceConfig(ts.URL, "alice", "phone", "555-0100"),
+				ExpectError: regexp.MustCompile(`Invalid Password Reset Target`),
+			},
+			{
+				Config:      testAccPasswordResetMethodResourceConfig(ts.URL, "alice", "email", "+15555550100"),
+				ExpectError: regexp.MustCompile(`is not an email\s+address`),
+			},
+			// Phone numbers are sent in E.164 format
+			{
+				Config: testAccPasswordResetMethodResourceConfig(ts.URL, "alice", "phone", "+1 (555) 555-0100"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_password_reset_method.test", "target", "+1 (555) 555-0100"),
+					testAccCheckPasswordResetTargets(ts.URL, "alice", "+15555550100"),
+				),
+			},
+			// Respelling the number updates in place
+			{
+				Config: testAccPasswordResetMethodResourceConfig(ts.URL, "alice", "phone", "001 555 555 0100"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_password_reset_method.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_password_reset_method.test", "target", "001 555 555 0100"),
+					testAccCheckPasswordResetTargets(ts.URL, "alice", "+15555550100"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// testAccCheckPasswordResetTargets checks the targets of the password reset
+// methods the mock server has for a user.
+func testAccCheckPasswordResetTargets(endpoint string, userName string, targets ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client, err := api.NewClient(endpoint)
+		if err != nil {
+			return err
+		}
+
+		httpResp, err := client.ListPasswordResetMethods(context.Background(), api.ListPasswordResetRequest{UserName: userName})
+		if err != nil {
+			return err
+		}
+		defer httpResp.Body.Close()
+
+		var listResp api.ListPasswordResetResponse
+		if err := json.NewDecoder(httpResp.Body).Decode(&listResp); err != nil {
+			return err
+		}
+
+		var got []string
+		if listResp.Result != nil && listResp.Result.Users != nil {
+			for _, method := range *listResp.Result.Users {
+				if method.Target != nil {
+					got = append(got, *method.Target)
+				}
+			}
+		}
+		if !slices.Equal(got, targets) {
+			return fmt.Errorf("password reset targets of %s are %q, want %q", userName, got, targets)
+		}
+
+		return nil
+	}
+}
+
+func TestAccPasswordResetMethodResourceMoveFromUser(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordResetMethodResourceConfigUser(ts.URL, `
+    "alice@recovery.example.com" = { type = "email", description = "Backup" }
+    "+15555550100"               = { type = "phone" }
+`),
+			},
+			// A user with several methods can't be moved to one resource
+			{
+				Config:      testAccPasswordResetMethodResourceConfigMoveFromUser(ts.URL),
+				ExpectError: regexp.MustCompile(`has 2 password reset methods in state`),
+			},
+			{
+				Config: testAccPasswordResetMethodResourceConfigUser(ts.URL, `
+    "alice@recovery.example.com" = { type = "email", description = "Backup" }
+`),
+			},
+			// The only method moves without API changes, the user is adopted again
+			{
+				Config: testAccPasswordResetMethodResourceConfigMoveFromUser(ts.URL),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_password_reset_method.test", plancheck.ResourceActionNoop),
+						plancheck.ExpectResourceAction("purelymail_user.adopted", plancheck.ResourceActionCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_password_reset_method.test", tfjsonpath.New("id"), knownvalue.StringExact("alice@example.com:alice@recovery.example.com")),
+					statecheck.ExpectKnownValue("purelymail_password_reset_method.test", tfjsonpath.New("description"), knownvalue.StringExact("Backup")),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "alice@example.com", "alice@recovery.example.com"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccPasswordResetMethodResourceConfigUser(endpoint string, methods string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name = "alice@example.com"
+
+  password_reset_methods = {
+%[2]s
+  }
+}
+`, endpoint, methods)
+}
+
+func testAccPasswordResetMethodResourceConfigMoveFromUser(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+moved {
+  from = purelymail_user.test
+  to   = purelymail_password_reset_method.test
+}
+
+resource "purelymail_user" "adopted" {
+  user_name                   = "alice@example.com"
+  adopt_existing              = true
+  password_reset_methods_mode = "additive"
+}
+
+resource "purelymail_password_reset_method" "test" {
+  user_name   = purelymail_user.adopted.user_name
+  type        = "email"
+  target      = "alice@recovery.example.com"
+  description = "Backup"
+}
+`, endpoint)
+}
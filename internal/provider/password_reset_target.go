@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ basetypes.StringTypable = PasswordResetTargetType{}
+var _ basetypes.StringValuableWithSemanticEquals = PasswordResetTargetValue{}
+
+// passwordResetMethodTypes are the valid values of a password reset method type.
+var passwordResetMethodTypes = []string{string(api.Email), string(api.Phone)}
+
+// normalizePhoneNumber returns a phone number in E.164 format, like
+// "+15555550100". Spaces, dashes, dots, slashes and parentheses are removed,
+// and an international "00" prefix is replaced by "+". Numbers without a
+// country code are rejected, as the country can't be guessed.
+func normalizePhoneNumber(number string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" \t-./()", r) {
+			return -1
+		}
+		return r
+	}, number)
+
+	if strings.HasPrefix(digits, "00") {
+		digits = "+" + digits[2:]
+	}
+	if !strings.HasPrefix(digits, "+") {
+		return "", fmt.Errorf("%q has no country code, use the international format like \"+15555550100\"", number)
+	}
+	digits = digits[1:]
+
+	if strings.Trim(digits, "0123456789") != "" {
+		return "", fmt.Errorf("%q is not a phone number", number)
+	}
+	if strings.HasPrefix(digits, "0") {
+		return "", fmt.Errorf("%q has an invalid country code", number)
+	}
+	// E.164 numbers have at most 15 digits, and the shortest numbers in use
+	// have 7 including the country code
+	if len(digits) < 7 || len(digits) > 15 {
+		return "", fmt.Errorf("%q must have between 7 and 15 digits including the country code", number)
+	}
+
+	return "+" + digits, nil
+}
+
+// normalizePasswordResetTarget validates and normalizes the target of a
+// password reset method of the given type.
+func normalizePasswordResetTarget(methodType string, target string) (string, error) {
+	switch api.UserPasswordResetMethodType(methodType) {
+	case api.Email:
+		if !strings.Contains(target, "@") {
+			return "", fmt.Errorf("%q is not an email address", target)
+		}
+		return normalizeEmailAddress(target)
+	case api.Phone:
+		return normalizePhoneNumber(target)
+	default:
+		return "", fmt.Errorf("unknown password reset method type %q", methodType)
+	}
+}
+
+// validatePasswordResetTarget checks that target is valid for the method type.
+// Unknown types are reported by the type validator.
+func validatePasswordResetTarget(targetPath path.Path, methodType types.String, target PasswordResetTargetValue) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if methodType.IsNull() || methodType.IsUnknown() || target.IsNull() || target.IsUnknown() ||
+		!slices.Contains(passwordResetMethodTypes, methodType.ValueString()) {
+		return diags
+	}
+
+	if _, err := normalizePasswordResetTarget(methodType.ValueString(), target.ValueString()); err != nil {
+		diags.AddAttributeError(
+			targetPath,
+			"Invalid Password Reset Target",
+			fmt.Sprintf("Attribute %s must be a valid %s target: %s", targetPath, methodType.ValueString(), err),
+		)
+	}
+
+	return diags
+}
+
+// guessPasswordResetTargetType returns the type of a target by its form, for
+// when the method type isn't at hand.
+func guessPasswordResetTargetType(target string) string {
+	if strings.Contains(target, "@") {
+		return string(api.Email)
+	}
+
+	return string(api.Phone)
+}
+
+// apiPasswordResetTarget returns the target to send to the API, normalized if
+// it is valid.
+func apiPasswordResetTarget(methodType string, target string) string {
+	normalized, err := normalizePasswordResetTarget(methodType, target)
+	if err != nil {
+		return target
+	}
+
+	return normalized
+}
+
+// normalizeAnyPasswordResetTarget normalizes a target of the type its form
+// suggests.
+func normalizeAnyPasswordResetTarget(target string) (string, error) {
+	return normalizePasswordResetTarget(guessPasswordResetTargetType(target), target)
+}
+
+// samePasswordResetTarget reports whether two targets are equal after
+// normalization.
+func samePasswordResetTarget(a, b string) bool {
+	return semanticallyEqual(normalizeAnyPasswordResetTarget, a, b)
+}
+
+// PasswordResetTargetType is a string type for password reset targets that
+// compares email addresses like EmailAddressType and phone numbers in E.164
+// format. Targets are validated together with the method type by the
+// resources.
+type PasswordResetTargetType struct {
+	basetypes.StringType
+}
+
+func (t PasswordResetTargetType) String() string {
+	return "PasswordResetTargetType"
+}
+
+func (t PasswordResetTargetType) Equal(o attr.Type) bool {
+	other, ok := o.(PasswordResetTargetType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t PasswordResetTargetType) ValueType(ctx context.Context) attr.Value {
+	return PasswordResetTargetValue{}
+}
+
+func (t PasswordResetTargetType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return PasswordResetTargetValue{StringValue: in}, nil
+}
+
+func (t PasswordResetTargetType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return PasswordResetTargetValue{StringValue: stringValue}, nil
+}
+
+// PasswordResetTargetValue is a value of PasswordResetTargetType.
+type PasswordResetTargetValue struct {
+	basetypes.StringValue
+}
+
+// NewPasswordResetTargetValue returns a known password reset target.
+func NewPasswordResetTargetValue(target string) PasswordResetTargetValue {
+	return PasswordResetTargetValue{StringValue: basetypes.NewStringValue(target)}
+}
+
+func (v PasswordResetTargetValue) Type(ctx context.Context) attr.Type {
+	return PasswordResetTargetType{}
+}
+
+func (v PasswordResetTargetValue) Equal(o attr.Value) bool {
+	other, ok := o.(PasswordResetTargetValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v PasswordResetTargetValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(PasswordResetTargetValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected PasswordResetTargetValue, got: %T. Please report this issue to the provider developers.", newValuable))
+		return false, diags
+	}
+
+	return samePasswordResetTarget(v.ValueString(), newValue.ValueString()), diags
+}
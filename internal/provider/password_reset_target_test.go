@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	cases := []struct {
+		number     string
+		normalized string
+		invalid    bool
+	}{
+		{number: "+15555550100", normalized: "+15555550100"},
+		{number: "+1 (555) 555-0100", normalized: "+15555550100"},
+		{number: "001.555.555.0100", normalized: "+15555550100"},
+		{number: "+49 30/1234567", normalized: "+49301234567"},
+		{number: "", invalid: true},
+		{number: "555-0100", invalid: true},
+		{number: "+0 555 555 0100", invalid: true},
+		{number: "+1 555 CALL NOW", invalid: true},
+		{number: "+12345", invalid: true},
+		{number: "+1234567890123456", invalid: true},
+	}
+	for _, c := range cases {
+		normalized, err := normalizePhoneNumber(c.number)
+		if c.invalid {
+			if err == nil {
+				t.Errorf("normalizePhoneNumber(%q) = %q, want an error", c.number, normalized)
+			}
+			continue
+		}
+		if err != nil || normalized != c.normalized {
+			t.Errorf("normalizePhoneNumber(%q) = %q, %v, want %q", c.number, normalized, err, c.normalized)
+		}
+	}
+}
+
+func TestSamePasswordResetTarget(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{a: "+15555550100", b: "+1 (555) 555-0100", same: true},
+		{a: "alice@Recovery.Example.com", b: "alice@recovery.example.com", same: true},
+		{a: "Alice@recovery.example.com", b: "alice@recovery.example.com", same: false},
+		{a: "+15555550100", b: "+15555550101", same: false},
+		{a: "555-0100", b: "5550100", same: false},
+	}
+	for _, c := range cases {
+		if same := samePasswordResetTarget(c.a, c.b); same != c.same {
+			t.Errorf("samePasswordResetTarget(%q, %q) = %t, want %t", c.a, c.b, same, c.same)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// keybasePrefix marks a PGP key that is fetched from keybase.io by user name,
+// like "keybase:alice".
+const keybasePrefix = "keybase:"
+
+// keybaseKeyURL is where the public key of a keybase user is fetched from,
+// with the user name in place of %s. Tests point it at a local server.
+var keybaseKeyURL = "https://keybase.io/%s/pgp_keys.asc"
+
+// resolvePGPKey returns the public key that pgpKey refers to, which is either
+// an ASCII-armored public key or a keybase: reference.
+func resolvePGPKey(ctx context.Context, pgpKey string) (*openpgp.Entity, error) {
+	userName, ok := strings.CutPrefix(pgpKey, keybasePrefix)
+	if !ok {
+		return parsePGPKey(pgpKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(keybaseKeyURL, url.PathEscape(userName)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the public key of keybase user %s: %w", userName, err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch the public key of keybase user %s (status: %d)", userName, httpResp.StatusCode)
+	}
+
+	armored, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the public key of keybase user %s: %w", userName, err)
+	}
+
+	return parsePGPKey(string(armored))
+}
+
+// parsePGPKey parses an ASCII-armored public key. Only the first key is used
+// if there are several.
+func parsePGPKey(armored string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse PGP public key: %w", err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no PGP public key found")
+	}
+
+	if _, ok := entities[0].EncryptionKey(time.Now()); !ok {
+		return nil, fmt.Errorf("PGP key %s has no valid encryption key", pgpKeyFingerprint(entities[0]))
+	}
+
+	return entities[0], nil
+}
+
+// pgpKeyFingerprint returns the fingerprint of the primary key, in uppercase
+// hex like gpg shows it.
+func pgpKeyFingerprint(entity *openpgp.Entity) string {
+	return strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))
+}
+
+// encryptWithPGPKey encrypts plaintext for entity and returns the
+// ASCII-armored message, which `gpg --decrypt` reads.
+func encryptWithPGPKey(entity *openpgp.Entity, plaintext string) (string, error) {
+	var buf bytes.Buffer
+
+	armorWriter, err := armor.Encode(&buf, "PGP MESSAGE", nil)
+	if err != nil {
+		return "", err
+	}
+
+	plaintextWriter, err := openpgp.Encrypt(armorWriter, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(plaintextWriter, plaintext); err != nil {
+		return "", err
+	}
+	if err := plaintextWriter.Close(); err != nil {
+		return "", err
+	}
+	if err := armorWriter.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// testPGPKey generates a key pair and returns it with its ASCII-armored
+// public key.
+func testPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return entity, buf.String()
+}
+
+// testPGPDecrypt decrypts an ASCII-armored message with the private key of
+// entity.
+func testPGPDecrypt(entity *openpgp.Entity, message string) (string, error) {
+	block, err := armor.Decode(strings.NewReader(message))
+	if err != nil {
+		return "", err
+	}
+
+	md, err := openpgp.ReadMessage(block.Body, openpgp.EntityList{entity}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := io.ReadAll(md.UnverifiedBody)
+	return string(plaintext), err
+}
+
+func TestEncryptWithPGPKey(t *testing.T) {
+	entity, armoredKey := testPGPKey(t)
+
+	key, err := resolvePGPKey(context.Background(), armoredKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pgpKeyFingerprint(key), pgpKeyFingerprint(entity); got != want {
+		t.Errorf("expected fingerprint %s, got %s", want, got)
+	}
+
+	encrypted, err := encryptWithPGPKey(key, "app-password-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(encrypted, "app-password-1") || !strings.HasPrefix(encrypted, "-----BEGIN PGP MESSAGE-----") {
+		t.Errorf("expected an armored PGP message, got %q", encrypted)
+	}
+
+	plaintext, err := testPGPDecrypt(entity, encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "app-password-1" {
+		t.Errorf("expected app-password-1, got %q", plaintext)
+	}
+}
+
+func TestResolvePGPKeyKeybase(t *testing.T) {
+	entity, armoredKey := testPGPKey(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alice/pgp_keys.asc" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, armoredKey)
+	}))
+	defer ts.Close()
+
+	defaultURL := keybaseKeyURL
+	keybaseKeyURL = ts.URL + "/%s/pgp_keys.asc"
+	defer func() { keybaseKeyURL = defaultURL }()
+
+	key, err := resolvePGPKey(context.Background(), "keybase:alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pgpKeyFingerprint(key), pgpKeyFingerprint(entity); got != want {
+		t.Errorf("expected fingerprint %s, got %s", want, got)
+	}
+
+	if _, err := resolvePGPKey(context.Background(), "keybase:bob"); err == nil || !strings.Contains(err.Error(), "status: 404") {
+		t.Errorf("expected an error for an unknown keybase user, got %v", err)
+	}
+}
+
+func TestParsePGPKeyInvalid(t *testing.T) {
+	if _, err := parsePGPKey("not a key"); err == nil {
+		t.Error("expected an error")
+	}
+}
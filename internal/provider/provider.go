@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -25,6 +26,7 @@ var _ provider.Provider = &PurelymailProvider{}
 var _ provider.ProviderWithFunctions = &PurelymailProvider{}
 var _ provider.ProviderWithEphemeralResources = &PurelymailProvider{}
 var _ provider.ProviderWithActions = &PurelymailProvider{}
+var _ provider.ProviderWithListResources = &PurelymailProvider{}
 
 // PurelymailProvider defines the provider implementation.
 type PurelymailProvider struct {
@@ -102,6 +104,7 @@ func (p *PurelymailProvider) Configure(ctx context.Context, req provider.Configu
 	resp.DataSourceData = client
 	resp.ResourceData = client
 	resp.EphemeralResourceData = client
+	resp.ListResourceData = client
 }
 
 func (p *PurelymailProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -111,6 +114,8 @@ func (p *PurelymailProvider) Resources(ctx context.Context) []func() resource.Re
 		NewAppPasswordResource,
 		NewDomainResource,
 		NewPasswordResetMethodResource,
+		NewCatchallResource,
+		NewUserTwoFactorResource,
 	}
 }
 
@@ -120,8 +125,18 @@ func (p *PurelymailProvider) EphemeralResources(ctx context.Context) []func() ep
 	}
 }
 
+func (p *PurelymailProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewUserListResource,
+		NewDomainListResource,
+		NewRoutingRuleListResource,
+		NewPasswordResetMethodListResource,
+	}
+}
+
 func (p *PurelymailProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewClientSettingsDataSource,
 		NewOwnershipProofDataSource,
 	}
 }
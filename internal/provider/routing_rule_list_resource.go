@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &RoutingRuleListResource{}
+var _ list.ListResourceWithConfigure = &RoutingRuleListResource{}
+
+func NewRoutingRuleListResource() list.ListResource {
+	return &RoutingRuleListResource{}
+}
+
+// RoutingRuleListResource defines the list resource implementation.
+type RoutingRuleListResource struct {
+	client *api.Client
+}
+
+// RoutingRuleListResourceModel describes the list resource config model.
+type RoutingRuleListResourceModel struct {
+	DomainName DomainNameValue `tfsdk:"domain_name"`
+	Catchall   types.Bool      `tfsdk:"catchall"`
+}
+
+func (r *RoutingRuleListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_routing_rule"
+}
+
+func (r *RoutingRuleListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the routing rules of the account.",
+
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				MarkdownDescription: "Only list routing rules of this domain.",
+				CustomType:          DomainNameType{},
+				Optional:            true,
+			},
+			"catchall": schema.BoolAttribute{
+				MarkdownDescription: "List only catch-all rules if true. Catch-all rules are skipped if false or unset, as they are usually managed by `purelymail_catchall`.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *RoutingRuleListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *RoutingRuleListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config RoutingRuleListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	rules, err := listRoutingRules(ctx, r.client)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list routing rules: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = limitListResults(req.Limit, func(push func(list.ListResult) bool) {
+		for _, rule := range rules {
+			if rule.Id == nil || rule.DomainName == nil || rule.MatchUser == nil || rule.Prefix == nil {
+				continue
+			}
+			if !config.DomainName.IsNull() && !sameDomainName(*rule.DomainName, config.DomainName.ValueString()) {
+				continue
+			}
+			catchall := rule.Catchall != nil && *rule.Catchall
+			if catchall != config.Catchall.ValueBool() {
+				continue
+			}
+
+			result := r.listResult(ctx, req, &rule)
+			if !push(result) || result.Diagnostics.HasError() {
+				return
+			}
+		}
+	})
+}
+
+// listResult returns the list result of a routing rule.
+func (r *RoutingRuleListResource) listResult(ctx context.Context, req list.ListRequest, rule *api.RoutingRule) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = routingRuleDisplayName(rule)
+
+	data := RoutingRuleResourceModel{
+		DomainName: NewDomainNameValue(*rule.DomainName),
+		MatchUser:  types.StringValue(*rule.MatchUser),
+		Prefix:     types.BoolValue(*rule.Prefix),
+	}
+	if req.IncludeResource {
+		var diags diag.Diagnostics
+		data, diags = importedResource[RoutingRuleResourceModel](ctx, result.Resource, path.Root("id"), int64(*rule.Id))
+		result.Diagnostics.Append(diags...)
+		if result.Diagnostics.HasError() {
+			return result
+		}
+
+		if err := setRoutingRule(ctx, &data, rule); err != nil {
+			return listResultError("Read Error", fmt.Sprintf("Unable to read routing rule %d: %s", *rule.Id, err))
+		}
+
+		result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+	}
+
+	result.Diagnostics.Append(result.Identity.Set(ctx, routingRuleIdentity(&data))...)
+
+	return result
+}
+
+// routingRuleDisplayName describes a routing rule by the addresses it matches
+// and where it routes them, like "info*@example.com -> alice@example.com".
+func routingRuleDisplayName(rule *api.RoutingRule) string {
+	match := *rule.MatchUser
+	if *rule.Prefix {
+		match += "*"
+	}
+
+	var targets []string
+	if rule.TargetAddresses != nil {
+		targets = *rule.TargetAddresses
+	}
+
+	return fmt.Sprintf("%s@%s -> %s", match, *rule.DomainName, strings.Join(targets, ", "))
+}
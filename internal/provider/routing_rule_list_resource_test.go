@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestAccRoutingRuleListResource(t *testing.T) {
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	supportIdentity := queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+		"domain_name": knownvalue.StringExact("example.com"),
+		"match_user":  knownvalue.StringExact("support"),
+		"prefix":      knownvalue.Bool(false),
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoutingRuleListResourceRulesConfig(ts.URL),
+			},
+			{
+				Query:  true,
+				Config: testAccRoutingRuleListResourceConfig("", true),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					// The catch-all rule is skipped without the catchall filter
+					querycheck.ExpectLength("purelymail_routing_rule.test", 2),
+					querycheck.ExpectResourceDisplayName("purelymail_routing_rule.test", supportIdentity,
+						knownvalue.StringExact("support@example.com -> team@example.com")),
+					querycheck.ExpectResourceKnownValues("purelymail_routing_rule.test", supportIdentity, []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("target_addresses"),
+							KnownValue: knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("team@example.com")}),
+						},
+						{
+							Path:       tfjsonpath.New("catchall"),
+							KnownValue: knownvalue.Bool(false),
+						},
+					}),
+				},
+			},
+			{
+				Query:  true,
+				Config: testAccRoutingRuleListResourceConfig(`domain_name = "EXAMPLE.org"`, false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_routing_rule.test", 1),
+					querycheck.ExpectIdentity("purelymail_routing_rule.test", map[string]knownvalue.Check{
+						"domain_name": knownvalue.StringExact("example.org"),
+						"match_user":  knownvalue.StringExact("sales"),
+						"prefix":      knownvalue.Bool(true),
+					}),
+				},
+			},
+			{
+				Query:  true,
+				Config: testAccRoutingRuleListResourceConfig("catchall = true", false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_routing_rule.test", 1),
+					querycheck.ExpectIdentity("purelymail_routing_rule.test", map[string]knownvalue.Check{
+						"domain_name": knownvalue.StringExact("example.com"),
+						"match_user":  knownvalue.StringExact(""),
+						"prefix":      knownvalue.Bool(true),
+					}),
+				},
+			},
+		},
+	})
+}
+
+func testAccRoutingRuleListResourceRulesConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_routing_rule" "support" {
+	domain_name      = "example.com"
+	prefix           = false
+	match_user       = "support"
+	target_addresses = ["team@example.com"]
+	catchall         = false
+}
+
+resource "purelymail_routing_rule" "sales" {
+	domain_name      = "example.org"
+	prefix           = true
+	match_user       = "sales"
+	target_addresses = ["sales-team@example.org"]
+}
+
+resource "purelymail_catchall" "test" {
+	domain_name      = "example.com"
+	target_addresses = ["inbox@example.com"]
+}
+`, endpoint)
+}
+
+// The query configs have no provider block, the one of the resources config
+// is used.
+func testAccRoutingRuleListResourceConfig(filter string, includeResource bool) string {
+	return fmt.Sprintf(`
+list "purelymail_routing_rule" "test" {
+	provider         = purelymail
+	include_resource = %[2]t
+
+	config {
+		%[1]s
+	}
+}
+`, filter, includeResource)
+}
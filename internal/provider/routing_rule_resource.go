@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
@@ -23,6 +25,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &RoutingRuleResource{}
 var _ resource.ResourceWithImportState = &RoutingRuleResource{}
+var _ resource.ResourceWithModifyPlan = &RoutingRuleResource{}
+var _ resource.ResourceWithIdentity = &RoutingRuleResource{}
 
 func NewRoutingRuleResource() resource.Resource {
 	return &RoutingRuleResource{}
@@ -35,18 +39,57 @@ type RoutingRuleResource struct {
 
 // RoutingRuleResourceModel describes the resource data model.
 type RoutingRuleResourceModel struct {
-	Id              types.Int64  `tfsdk:"id"`
-	DomainName      types.String `tfsdk:"domain_name"`
-	Prefix          types.Bool   `tfsdk:"prefix"`
-	MatchUser       types.String `tfsdk:"match_user"`
-	TargetAddresses types.List   `tfsdk:"target_addresses"`
-	Catchall        types.Bool   `tfsdk:"catchall"`
+	Id              types.Int64     `tfsdk:"id"`
+	DomainName      DomainNameValue `tfsdk:"domain_name"`
+	Prefix          types.Bool      `tfsdk:"prefix"`
+	MatchUser       types.String    `tfsdk:"match_user"`
+	TargetAddresses types.List      `tfsdk:"target_addresses"`
+	Catchall        types.Bool      `tfsdk:"catchall"`
+	Timeouts        timeouts.Value  `tfsdk:"timeouts"`
+}
+
+// RoutingRuleResourceIdentityModel describes the resource identity. Rule IDs
+// change whenever a rule is updated, so rules are identified by what they
+// match instead.
+type RoutingRuleResourceIdentityModel struct {
+	DomainName types.String `tfsdk:"domain_name"`
+	MatchUser  types.String `tfsdk:"match_user"`
+	Prefix     types.Bool   `tfsdk:"prefix"`
+}
+
+// routingRuleIdentity returns the identity of a routing rule, with the domain
+// name normalized.
+func routingRuleIdentity(data *RoutingRuleResourceModel) RoutingRuleResourceIdentityModel {
+	return RoutingRuleResourceIdentityModel{
+		DomainName: types.StringValue(normalizedOrRaw(normalizeDomainName, data.DomainName.ValueString())),
+		MatchUser:  data.MatchUser,
+		Prefix:     data.Prefix,
+	}
 }
 
 func (r *RoutingRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_routing_rule"
 }
 
+func (r *RoutingRuleResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain_name": identityschema.StringAttribute{
+				Description:       "The domain name of the routing rule.",
+				RequiredForImport: true,
+			},
+			"match_user": identityschema.StringAttribute{
+				Description:       "The username or prefix the routing rule matches.",
+				RequiredForImport: true,
+			},
+			"prefix": identityschema.BoolAttribute{
+				Description:       "Whether the routing rule is a prefix match.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *RoutingRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a Purelymail routing rule for a domain.",
@@ -61,9 +104,10 @@ func (r *RoutingRuleResource) Schema(ctx context.Context, req resource.SchemaReq
 			},
 			"domain_name": schema.StringAttribute{
 				MarkdownDescription: "The domain name for this routing rule.",
+				CustomType:          DomainNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessSemanticallyEqual(DomainNameType{}),
 				},
 			},
 			"prefix": schema.BoolAttribute{
@@ -83,7 +127,7 @@ func (r *RoutingRuleResource) Schema(ctx context.Context, req resource.SchemaReq
 			"target_addresses": schema.ListAttribute{
 				MarkdownDescription: "List of target email addresses to route matching emails to.",
 				Required:            true,
-				ElementType:         types.StringType,
+				ElementType:         EmailAddressType{},
 			},
 			"catchall": schema.BoolAttribute{
 				MarkdownDescription: "Whether this is a catch-all rule.",
@@ -91,6 +135,9 @@ func (r *RoutingRuleResource) Schema(ctx context.Context, req resource.SchemaReq
 				Computed:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
 	}
 }
 
@@ -119,6 +166,13 @@ func (r *RoutingRuleResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	// Convert target addresses from types.List to []string
 	var targetAddresses []string
 	resp.Diagnostics.Append(data.TargetAddresses.ElementsAs(ctx, &targetAddresses, false)...)
@@ -139,6 +193,7 @@ func (r *RoutingRuleResource) Create(ctx context.Context, req resource.CreateReq
 		createReq.Catchall = &catchall
 	}
 
+	setStep(ctx, "create routing rule")
 	httpResp, err := r.client.CreateRoutingRule(ctx, createReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create routing rule: %s", err))
@@ -165,6 +220,7 @@ func (r *RoutingRuleResource) Create(ctx context.Context, req resource.CreateReq
 	tflog.Trace(ctx, "created purelymail_routing_rule resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, routingRuleIdentity(&data))...)
 }
 
 func (r *RoutingRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -175,6 +231,13 @@ func (r *RoutingRuleResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "read", data.Timeouts.Read, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	if err := r.readRoutingRule(ctx, &data); err != nil {
 		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read routing rule: %s", err))
 		return
@@ -183,6 +246,7 @@ func (r *RoutingRuleResource) Read(ctx context.Context, req resource.ReadRequest
 	tflog.Trace(ctx, "read purelymail_routing_rule resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, routingRuleIdentity(&data))...)
 }
 
 func (r *RoutingRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -195,12 +259,22 @@ func (r *RoutingRuleResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "update", data.Timeouts.Update, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	keepPriorSpelling(&data.DomainName, state.DomainName)
+
 	// Routing rules don't have an update API, so we need to delete and recreate
 	// First, delete the old rule
 	deleteReq := api.DeleteRoutingRequest{
 		RoutingRuleId: int32(state.Id.ValueInt64()),
 	}
 
+	setStep(ctx, "delete routing rule")
 	httpResp, err := r.client.DeleteRoutingRule(ctx, deleteReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete routing rule during update: %s", err))
@@ -233,6 +307,7 @@ func (r *RoutingRuleResource) Update(ctx context.Context, req resource.UpdateReq
 		createReq.Catchall = &catchall
 	}
 
+	setStep(ctx, "create routing rule")
 	httpResp, err = r.client.CreateRoutingRule(ctx, createReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create routing rule during update: %s", err))
@@ -257,6 +332,7 @@ func (r *RoutingRuleResource) Update(ctx context.Context, req resource.UpdateReq
 	tflog.Trace(ctx, "updated purelymail_routing_rule resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, routingRuleIdentity(&data))...)
 }
 
 func (r *RoutingRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -267,10 +343,18 @@ func (r *RoutingRuleResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	deleteReq := api.DeleteRoutingRequest{
 		RoutingRuleId: int32(data.Id.ValueInt64()),
 	}
 
+	setStep(ctx, "delete routing rule")
 	httpResp, err := r.client.DeleteRoutingRule(ctx, deleteReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete routing rule: %s", err))
@@ -286,7 +370,41 @@ func (r *RoutingRuleResource) Delete(ctx context.Context, req resource.DeleteReq
 	tflog.Trace(ctx, "deleted purelymail_routing_rule resource")
 }
 
+func (r *RoutingRuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state RoutingRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.DomainName.Equal(state.DomainName) {
+		resp.Diagnostics.Append(warnUnknownDomain(ctx, r.client, path.Root("domain_name"), plan.DomainName.StringValue)...)
+	}
+}
+
 func (r *RoutingRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" {
+		// Import by identity, the rule is looked up by what it matches
+		var identity RoutingRuleResourceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_name"), identity.DomainName.ValueString())...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("match_user"), identity.MatchUser)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("prefix"), identity.Prefix)...)
+		return
+	}
+
 	// Import by ID
 	id, err := strconv.ParseInt(req.ID, 10, 64)
 	if err != nil {
@@ -299,6 +417,7 @@ func (r *RoutingRuleResource) ImportState(ctx context.Context, req resource.Impo
 
 // readRoutingRule reads a routing rule from the API and updates the model.
 func (r *RoutingRuleResource) readRoutingRule(ctx context.Context, data *RoutingRuleResourceModel) error {
+	setStep(ctx, "read routing rule")
 	httpResp, err := r.client.ListRoutingRules(ctx, api.EmptyRequest{})
 	if err != nil {
 		return fmt.Errorf("unable to list routing rules: %w", err)
@@ -355,7 +474,7 @@ func (r *RoutingRuleResource) readRoutingRule(ctx context.Context, data *Routing
 				"target_match":  data.MatchUser.ValueString(),
 				"target_prefix": data.Prefix.ValueBool(),
 			})
-			if rule.DomainName != nil && *rule.DomainName == data.DomainName.ValueString() &&
+			if rule.DomainName != nil && sameDomainName(*rule.DomainName, data.DomainName.ValueString()) &&
 				rule.MatchUser != nil && *rule.MatchUser == data.MatchUser.ValueString() &&
 				rule.Prefix != nil && *rule.Prefix == data.Prefix.ValueBool() {
 				ruleCopy := rule
@@ -372,12 +491,16 @@ func (r *RoutingRuleResource) readRoutingRule(ctx context.Context, data *Routing
 			data.Prefix.ValueBool(), data.Id)
 	}
 
-	// Update the model
+	return setRoutingRule(ctx, data, foundRule)
+}
+
+// setRoutingRule updates the model with a routing rule as the API lists it.
+func setRoutingRule(ctx context.Context, data *RoutingRuleResourceModel, foundRule *api.RoutingRule) error {
 	if foundRule.Id != nil {
 		data.Id = types.Int64Value(int64(*foundRule.Id))
 	}
 	if foundRule.DomainName != nil {
-		data.DomainName = types.StringValue(*foundRule.DomainName)
+		data.DomainName = NewDomainNameValue(*foundRule.DomainName)
 	}
 	if foundRule.Prefix != nil {
 		data.Prefix = types.BoolValue(*foundRule.Prefix)
@@ -386,7 +509,7 @@ func (r *RoutingRuleResource) readRoutingRule(ctx context.Context, data *Routing
 		data.MatchUser = types.StringValue(*foundRule.MatchUser)
 	}
 	if foundRule.TargetAddresses != nil {
-		targetList, diags := types.ListValueFrom(ctx, types.StringType, *foundRule.TargetAddresses)
+		targetList, diags := types.ListValueFrom(ctx, EmailAddressType{}, *foundRule.TargetAddresses)
 		if diags.HasError() {
 			return fmt.Errorf("unable to convert target addresses")
 		}
@@ -400,3 +523,27 @@ func (r *RoutingRuleResource) readRoutingRule(ctx context.Context, data *Routing
 
 	return nil
 }
+
+// listRoutingRules returns all routing rules of the account.
+func listRoutingRules(ctx context.Context, client *api.Client) ([]api.RoutingRule, error) {
+	httpResp, err := client.ListRoutingRules(ctx, api.EmptyRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list routing rules: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list routing rules (status: %d)", httpResp.StatusCode)
+	}
+
+	var listResp api.ListRoutingResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	if listResp.Result == nil || listResp.Result.Rules == nil {
+		return nil, nil
+	}
+
+	return *listResp.Result.Rules, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
 )
@@ -64,6 +65,40 @@ func TestAccRoutingRuleResource(t *testing.T) {
 	})
 }
 
+func TestAccRoutingRuleResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoutingRuleResourceConfig(ts.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_routing_rule.test", map[string]knownvalue.Check{
+						"domain_name": knownvalue.StringExact("example.com"),
+						"match_user":  knownvalue.StringExact("support"),
+						"prefix":      knownvalue.Bool(false),
+					}),
+				},
+			},
+			{
+				ResourceName:    "purelymail_routing_rule.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
 func testAccRoutingRuleResourceConfig(endpoint string) string {
 	return `
 provider "purelymail" {
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// Default timeouts of resource operations. Creating and updating domains may
+// wait for DNS checks, which wait_for_dns bounds at 15 minutes by default.
+const (
+	defaultCreateTimeout = 30 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 30 * time.Minute
+	defaultDeleteTimeout = 10 * time.Minute
+)
+
+// cleanupTimeout bounds the API calls that clean up after an operation
+// failed, like rolling back a partially created user. They get their own
+// deadline, as the operation may have failed because its timeout expired.
+const cleanupTimeout = time.Minute
+
+// timeoutsBlock returns the timeouts block of a resource. Operations that make
+// no API calls have no timeout.
+func timeoutsBlock(ctx context.Context, opts timeouts.Opts) schema.Block {
+	describe := func(operation string, defaultTimeout time.Duration) string {
+		return fmt.Sprintf("How long to wait for the resource to be %s, as a duration like '10m'. Defaults to '%s'.",
+			operation, strings.TrimSuffix(defaultTimeout.String(), "0s"))
+	}
+
+	if opts.Create {
+		opts.CreateDescription = describe("created", defaultCreateTimeout)
+	}
+	if opts.Read {
+		opts.ReadDescription = describe("read", defaultReadTimeout)
+	}
+	if opts.Update {
+		opts.UpdateDescription = describe("updated", defaultUpdateTimeout)
+	}
+	if opts.Delete {
+		opts.DeleteDescription = describe("deleted", defaultDeleteTimeout)
+	}
+
+	return timeouts.Block(ctx, opts)
+}
+
+// operation is a create, read, update or delete operation that is bounded by
+// a timeout. It tracks the step it is running, so a timeout can name it.
+type operation struct {
+	name    string
+	timeout time.Duration
+	step    string
+}
+
+type operationKey struct{}
+
+// startOperation starts the operation called name ("create", "read", "update"
+// or "delete") with the timeout that timeout returns from the timeouts block,
+// or defaultTimeout if it isn't set. The returned context ends when the
+// timeout expires. finish must be called when the operation returns, and adds
+// an error naming the running step if the timeout expired.
+func startOperation(
+	ctx context.Context,
+	name string,
+	timeout func(context.Context, time.Duration) (time.Duration, diag.Diagnostics),
+	defaultTimeout time.Duration,
+) (context.Context, func(*diag.Diagnostics), diag.Diagnostics) {
+	d, diags := timeout(ctx, defaultTimeout)
+	if diags.HasError() {
+		return ctx, func(*diag.Diagnostics) {}, diags
+	}
+
+	op := &operation{name: name, timeout: d}
+	ctx = context.WithValue(ctx, operationKey{}, op)
+	ctx, cancel := context.WithTimeout(ctx, d)
+
+	finish := func(diags *diag.Diagnostics) {
+		defer cancel()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			diags.AddError("Operation Timed Out", op.timeoutMessage())
+		}
+	}
+
+	return ctx, finish, diags
+}
+
+// timeoutMessage describes an expired timeout of the operation.
+func (op *operation) timeoutMessage() string {
+	message := fmt.Sprintf("The %s operation did not finish within %s", op.name, op.timeout)
+	if op.step != "" {
+		message += fmt.Sprintf(", it timed out during step %q", op.step)
+	}
+
+	return message + fmt.Sprintf(". Set a longer %s timeout in the timeouts block if it needs more time.", op.name)
+}
+
+// setStep records the step that the operation of ctx is running. It does
+// nothing outside of an operation, like during planning.
+func setStep(ctx context.Context, step string) {
+	if op, ok := ctx.Value(operationKey{}).(*operation); ok && op != nil {
+		op.step = step
+	}
+}
+
+// cleanupContext returns a context for cleaning up after a failed operation,
+// which ends after cleanupTimeout even if the operation's timeout expired.
+// Cleanup steps aren't recorded, so a timeout still names the failed step.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(context.WithoutCancel(ctx), operationKey{}, (*operation)(nil))
+
+	return context.WithTimeout(ctx, cleanupTimeout)
+}
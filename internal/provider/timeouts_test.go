@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestStartOperation(t *testing.T) {
+	timeout := func(d time.Duration) func(context.Context, time.Duration) (time.Duration, diag.Diagnostics) {
+		return func(context.Context, time.Duration) (time.Duration, diag.Diagnostics) {
+			return d, nil
+		}
+	}
+
+	// Finished in time
+	ctx, finish, diags := startOperation(context.Background(), "read", timeout(time.Minute), defaultReadTimeout)
+	setStep(ctx, "read user")
+	finish(&diags)
+	if diags.HasError() {
+		t.Errorf("expected no error, got %v", diags)
+	}
+
+	// Timed out, cleanup steps don't replace the step that timed out
+	ctx, finish, diags = startOperation(context.Background(), "create", timeout(10*time.Millisecond), defaultCreateTimeout)
+	setStep(ctx, "modify user")
+	<-ctx.Done()
+
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+	if cleanupCtx.Err() != nil {
+		t.Errorf("expected the cleanup context to outlive the operation, got %s", cleanupCtx.Err())
+	}
+	setStep(cleanupCtx, "delete user")
+
+	finish(&diags)
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail(), `step "modify user"`) {
+		t.Errorf("expected a timeout naming the modify user step, got %v", diags)
+	}
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &UserListResource{}
+var _ list.ListResourceWithConfigure = &UserListResource{}
+
+func NewUserListResource() list.ListResource {
+	return &UserListResource{}
+}
+
+// UserListResource defines the list resource implementation.
+type UserListResource struct {
+	client *api.Client
+}
+
+// UserListResourceModel describes the list resource config model.
+type UserListResourceModel struct {
+	DomainName DomainNameValue `tfsdk:"domain_name"`
+}
+
+func (r *UserListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *UserListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the users of the account.",
+
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				MarkdownDescription: "Only list users of this domain.",
+				CustomType:          DomainNameType{},
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *UserListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UserListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config UserListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	userNames, err := listUsers(ctx, r.client)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list users: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = limitListResults(req.Limit, func(push func(list.ListResult) bool) {
+		for _, userName := range userNames {
+			if !config.DomainName.IsNull() {
+				domainName, ok := emailDomain(userName)
+				if !ok || !sameDomainName(domainName, config.DomainName.ValueString()) {
+					continue
+				}
+			}
+
+			result, ok := r.listResult(ctx, req, userName)
+			if !ok {
+				// The user was deleted since it was listed
+				continue
+			}
+			if !push(result) || result.Diagnostics.HasError() {
+				return
+			}
+		}
+	})
+}
+
+// listResult returns the list result of a user. The user is only read if
+// Terraform asked for the resource, and false is returned if it no longer
+// exists.
+func (r *UserListResource) listResult(ctx context.Context, req list.ListRequest, userName string) (list.ListResult, bool) {
+	result := req.NewListResult(ctx)
+	result.DisplayName = userName
+
+	data := UserResourceModel{UserName: NewMailboxNameValue(userName)}
+	if req.IncludeResource {
+		var diags diag.Diagnostics
+		data, diags = importedResource[UserResourceModel](ctx, result.Resource, path.Root("user_name"), userName)
+		result.Diagnostics.Append(diags...)
+		if result.Diagnostics.HasError() {
+			return result, true
+		}
+
+		found, err := (&UserResource{client: r.client}).readUser(ctx, &data)
+		if err != nil {
+			return listResultError("Read Error", fmt.Sprintf("Unable to read user %s: %s", userName, err)), true
+		}
+		if !found {
+			return result, false
+		}
+
+		data.Id = types.StringValue(userName)
+		setImportedUserDefaults(&data)
+
+		result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+	}
+
+	result.Diagnostics.Append(result.Identity.Set(ctx, userIdentity(&data))...)
+
+	return result, true
+}
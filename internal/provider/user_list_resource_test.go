@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestAccUserListResource(t *testing.T) {
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserListResourceUsersConfig(ts.URL),
+			},
+			// All users
+			{
+				Query:  true,
+				Config: testAccUserListResourceConfig("", false),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_user.test", 3),
+					querycheck.ExpectIdentity("purelymail_user.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("alice@example.com"),
+					}),
+					querycheck.ExpectIdentity("purelymail_user.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("carol@example.org"),
+					}),
+				},
+			},
+			// Filtered by domain, with the resources for generating config
+			{
+				Query:  true,
+				Config: testAccUserListResourceConfig("Example.COM", true),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_user.test", 2),
+					querycheck.ExpectResourceDisplayName("purelymail_user.test", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("bob@example.com"),
+					}), knownvalue.StringExact("bob@example.com")),
+					querycheck.ExpectResourceKnownValues("purelymail_user.test", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("bob@example.com"),
+					}), []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("enable_search_indexing"),
+							KnownValue: knownvalue.Bool(false),
+						},
+						{
+							Path:       tfjsonpath.New("password_reset_methods_mode"),
+							KnownValue: knownvalue.StringExact("authoritative"),
+						},
+						{
+							Path:       tfjsonpath.New("deletion_protection"),
+							KnownValue: knownvalue.Bool(false),
+						},
+					}),
+				},
+			},
+			// Terraform's limit stops the listing
+			{
+				Query:  true,
+				Config: testAccUserListResourceConfigLimit(1),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("purelymail_user.test", 1),
+				},
+			},
+		},
+	})
+}
+
+func testAccUserListResourceUsersConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+	endpoint  = %[1]q
+	api_token = "test-token"
+}
+
+resource "purelymail_user" "alice" {
+	user_name = "alice@example.com"
+	password  = "alice-password"
+}
+
+resource "purelymail_user" "bob" {
+	user_name              = "bob@example.com"
+	password               = "bob-password"
+	enable_search_indexing = false
+}
+
+resource "purelymail_user" "carol" {
+	user_name = "carol@example.org"
+	password  = "carol-password"
+}
+`, endpoint)
+}
+
+// The query configs have no provider block, the one of the resources
+// config is used.
+func testAccUserListResourceConfig(domainName string, includeResource bool) string {
+	filter := ""
+	if domainName != "" {
+		filter = fmt.Sprintf("domain_name = %q", domainName)
+	}
+
+	return fmt.Sprintf(`
+list "purelymail_user" "test" {
+	provider         = purelymail
+	include_resource = %[1]t
+
+	config {
+		%[2]s
+	}
+}
+`, includeResource, filter)
+}
+
+func testAccUserListResourceConfigLimit(limit int) string {
+	return fmt.Sprintf(`
+list "purelymail_user" "test" {
+	provider = purelymail
+	limit    = %[1]d
+}
+`, limit)
+}
@@ -4,16 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
-
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
@@ -22,6 +36,11 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &UserResource{}
 var _ resource.ResourceWithImportState = &UserResource{}
+var _ resource.ResourceWithModifyPlan = &UserResource{}
+var _ resource.ResourceWithUpgradeState = &UserResource{}
+var _ resource.ResourceWithConfigValidators = &UserResource{}
+var _ resource.ResourceWithValidateConfig = &UserResource{}
+var _ resource.ResourceWithIdentity = &UserResource{}
 
 func NewUserResource() resource.Resource {
 	return &UserResource{}
@@ -34,77 +53,190 @@ type UserResource struct {
 
 // UserResourceModel describes the resource data model.
 type UserResourceModel struct {
-	UserName                       types.String `tfsdk:"user_name"`
-	NewUserName                    types.String `tfsdk:"new_user_name"`
-	Password                       types.String `tfsdk:"password"`
-	PasswordWo                     types.String `tfsdk:"password_wo"`
-	EnableSearchIndexing           types.Bool   `tfsdk:"enable_search_indexing"`
-	RequireTwoFactorAuthentication types.Bool   `tfsdk:"require_two_factor_authentication"`
-	PasswordResetMethods           types.List   `tfsdk:"password_reset_methods"`
-	Id                             types.String `tfsdk:"id"`
+	UserName                       MailboxNameValue `tfsdk:"user_name"`
+	NewUserName                    MailboxNameValue `tfsdk:"new_user_name"`
+	Password                       types.String     `tfsdk:"password"`
+	PasswordHash                   types.String     `tfsdk:"password_hash"`
+	PasswordWo                     types.String     `tfsdk:"password_wo"`
+	PasswordWoVersion              types.Int64      `tfsdk:"password_wo_version"`
+	EnableSearchIndexing           types.Bool       `tfsdk:"enable_search_indexing"`
+	RequireTwoFactorAuthentication types.Bool       `tfsdk:"require_two_factor_authentication"`
+	PasswordResetMethods           types.Map        `tfsdk:"password_reset_methods"`
+	PasswordResetMethodsMode       types.String     `tfsdk:"password_reset_methods_mode"`
+	DeletionProtection             types.Bool       `tfsdk:"deletion_protection"`
+	AdoptExisting                  types.Bool       `tfsdk:"adopt_existing"`
+	OnCreateFailure                types.String     `tfsdk:"on_create_failure"`
+	OnDestroyForwardTo             types.List       `tfsdk:"on_destroy_forward_to"`
+	Id                             types.String     `tfsdk:"id"`
+	Timeouts                       timeouts.Value   `tfsdk:"timeouts"`
+}
+
+// Values of on_create_failure.
+const (
+	onCreateFailureRollback = "rollback"
+	onCreateFailureTaint    = "taint"
+)
+
+// Values of password_reset_methods_mode.
+const (
+	passwordResetMethodsAuthoritative = "authoritative"
+	passwordResetMethodsAdditive      = "additive"
+	passwordResetMethodsIgnore        = "ignore"
+)
+
+// passwordResetMethodsMode returns the password_reset_methods_mode of a
+// model, which is authoritative if it isn't set.
+func passwordResetMethodsMode(data *UserResourceModel) string {
+	if data.PasswordResetMethodsMode.IsNull() || data.PasswordResetMethodsMode.IsUnknown() {
+		return passwordResetMethodsAuthoritative
+	}
+
+	return data.PasswordResetMethodsMode.ValueString()
 }
 
 // PasswordResetMethodModel describes a password reset method nested object.
+// Target is the key of the object in password_reset_methods.
 type PasswordResetMethodModel struct {
+	Target        string       `tfsdk:"-"`
 	Type          types.String `tfsdk:"type"`
-	Target        types.String `tfsdk:"target"`
 	Description   types.String `tfsdk:"description"`
 	AllowMfaReset types.Bool   `tfsdk:"allow_mfa_reset"`
 }
 
+// passwordResetMethodAttrTypes are the attribute types of a password reset
+// method nested object.
+var passwordResetMethodAttrTypes = map[string]attr.Type{
+	"type":            types.StringType,
+	"description":     types.StringType,
+	"allow_mfa_reset": types.BoolType,
+}
+
+// passwordResetMethodList returns the methods of a password_reset_methods map
+// ordered by target, with Target set from the map keys.
+func passwordResetMethodList(ctx context.Context, methods types.Map) ([]PasswordResetMethodModel, diag.Diagnostics) {
+	if methods.IsNull() || methods.IsUnknown() {
+		return nil, nil
+	}
+
+	var byTarget map[string]PasswordResetMethodModel
+	diags := methods.ElementsAs(ctx, &byTarget, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	list := make([]PasswordResetMethodModel, 0, len(byTarget))
+	for _, target := range slices.Sorted(maps.Keys(byTarget)) {
+		method := byTarget[target]
+		method.Target = target
+		list = append(list, method)
+	}
+
+	return list, diags
+}
+
+// passwordResetMethodTarget returns the target of a method as sent to the API.
+func passwordResetMethodTarget(method PasswordResetMethodModel) string {
+	return apiPasswordResetTarget(method.Type.ValueString(), method.Target)
+}
+
+// UserResourceIdentityModel describes the resource identity.
+type UserResourceIdentityModel struct {
+	UserName types.String `tfsdk:"user_name"`
+}
+
+// userIdentity returns the identity of a user, with the domain of the user
+// name normalized.
+func userIdentity(data *UserResourceModel) UserResourceIdentityModel {
+	return UserResourceIdentityModel{
+		UserName: types.StringValue(normalizedOrRaw(normalizeMailboxName, data.UserName.ValueString())),
+	}
+}
+
 func (r *UserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_user"
+	// new_user_name renames the user
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+func (r *UserResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"user_name": identityschema.StringAttribute{
+				Description:       "The username (email address) of the user.",
+				RequiredForImport: true,
+			},
+		},
+	}
 }
 
 func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a Purelymail user account.",
+		Version:             2,
 
 		Attributes: map[string]schema.Attribute{
 			"user_name": schema.StringAttribute{
-				MarkdownDescription: "The full email address like 'alice@example.com'. Cannot be changed after creation.",
+				MarkdownDescription: "The full email address like 'alice@example.com'. Cannot be changed after creation, except for the spelling of the domain.",
+				CustomType:          MailboxNameType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessSemanticallyEqual(MailboxNameType{}),
 				},
 			},
 			"new_user_name": schema.StringAttribute{
-				MarkdownDescription: "New username to rename the user (write-only, only used during updates).",
+				MarkdownDescription: "New username to rename the user (write-only, only used during updates). Must differ from `user_name` and be on one of the account's domains.",
+				CustomType:          MailboxNameType{},
 				Optional:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "The user's password. This remains in state (sensitive but visible in state files). Useful for tracking password changes.",
+				MarkdownDescription: "The user's password. Only a salted hash is stored in state, in `password_hash`, so changing the password is still detected during plan. Conflicts with `password_wo`.",
 				Optional:            true,
 				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"password_hash": schema.StringAttribute{
+				MarkdownDescription: "Salted argon2id hash of `password`, used to detect password changes.",
+				Computed:            true,
+				Sensitive:           true,
 			},
 			"password_wo": schema.StringAttribute{
-				MarkdownDescription: "The user's password (write-only, never stored in state, more secure but password changes cannot be detected by Terraform).",
+				MarkdownDescription: "The user's password (write-only, never stored in state, more secure but password changes cannot be detected by Terraform). It is sent when the user is created, and afterwards only when `password_wo_version` changes.",
 				Optional:            true,
 				Sensitive:           true,
 				WriteOnly:           true,
 			},
+			"password_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `password_wo`. Change it to send a new `password_wo` to Purelymail.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("password_wo")),
+				},
+			},
 			"enable_search_indexing": schema.BoolAttribute{
 				MarkdownDescription: "Whether to enable search indexing for this user.",
 				Optional:            true,
 				Computed:            true,
 			},
 			"require_two_factor_authentication": schema.BoolAttribute{
-				MarkdownDescription: "Whether to require two-factor authentication for this user. Note: At least one password reset method must be configured before this can be enabled.",
+				MarkdownDescription: "Whether to require two-factor authentication for this user. Requires at least one entry in `password_reset_methods`, unless `password_reset_methods_mode` is 'additive' or 'ignore'. Leave unset if 2FA is managed with a `purelymail_user_two_factor` resource.",
 				Optional:            true,
 				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					// Keep 2FA as is when it is managed with purelymail_user_two_factor
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"password_reset_methods": schema.ListNestedAttribute{
-				MarkdownDescription: "Password reset methods for this user. At least one is required if two-factor authentication is enabled.",
+			"password_reset_methods": schema.MapNestedAttribute{
+				MarkdownDescription: "Password reset methods for this user, keyed by target: an email address, or a phone number in international format like '+15555550100'. Phone numbers are sent in E.164 format. At least one is required if two-factor authentication is enabled.",
 				Optional:            true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"type": schema.StringAttribute{
 							MarkdownDescription: "The type of password reset method. Valid values: 'email', 'phone'.",
 							Required:            true,
-						},
-						"target": schema.StringAttribute{
-							MarkdownDescription: "The target for the password reset method (email address or phone number).",
-							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(passwordResetMethodTypes...),
+							},
 						},
 						"description": schema.StringAttribute{
 							MarkdownDescription: "An optional description for this password reset method.",
@@ -119,11 +251,53 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 					},
 				},
 			},
+			"password_reset_methods_mode": schema.StringAttribute{
+				MarkdownDescription: "How `password_reset_methods` manages the user's password reset methods. 'authoritative' reads all methods and deletes those not in `password_reset_methods`. 'additive' only reads and changes the methods in `password_reset_methods`, so others can be managed with `purelymail_password_reset_method` resources. 'ignore' leaves all methods alone and requires `password_reset_methods` to be unset. Defaults to 'authoritative'.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(passwordResetMethodsAuthoritative),
+				Validators: []validator.String{
+					stringvalidator.OneOf(passwordResetMethodsAuthoritative, passwordResetMethodsAdditive, passwordResetMethodsIgnore),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether to refuse deleting the user, including replacements. Must be set to false and applied before the user can be destroyed.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to take over a user that already exists instead of failing. The existing user's settings and password reset methods are changed to match the configuration.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"on_create_failure": schema.StringAttribute{
+				MarkdownDescription: "What to do when a step after creating the user fails, such as adding a password reset method or enabling 2FA. 'rollback' deletes the user again. 'taint' saves the partially created user to state as tainted, listing the unfinished steps, so it is replaced on the next apply. Defaults to 'rollback'.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(onCreateFailureRollback),
+				Validators: []validator.String{
+					stringvalidator.OneOf(onCreateFailureRollback, onCreateFailureTaint),
+				},
+			},
+			"on_destroy_forward_to": schema.ListAttribute{
+				MarkdownDescription: "Addresses to forward mail for `user_name` to after the user is destroyed, so it doesn't bounce. After deleting the user, an exact-match routing rule for its address is created and its ID is reported in a warning, so it can be imported as a `purelymail_routing_rule` or deleted later. If an exact-match rule for the address already exists, no rule is added. Requires `user_name` to be a full email address. Replacing the user, including with `-replace` or after `on_create_failure = \"taint\"`, deletes it and so also forwards its mail. Only the applied value is used, so apply changes before destroying the user.",
+				ElementType:         EmailAddressType{},
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The user identifier (same as user_name).",
 				Computed:            true,
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
 	}
 }
 
@@ -148,135 +322,87 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 	var data UserResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// password and password_wo are write-only, so they are only available from the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &data.Password)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &data.PasswordWo)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Step 1: Create user via API
-	httpResp, err := r.client.CreateUser(ctx, api.CreateUserJSONRequestBody{
-		UserName: data.UserName.ValueString(),
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create user: %s", err))
-		return
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to create user (status: %d)", httpResp.StatusCode))
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	defer finish(&resp.Diagnostics)
 
-	// Build modify request for initial settings (without 2FA)
-	modifyReq := api.ModifyUserJSONRequestBody{
-		UserName: data.UserName.ValueString(),
-	}
-	hasModifications := false
-
-	// Handle password (prefer password_wo if both are set)
-	if !data.PasswordWo.IsNull() && !data.PasswordWo.IsUnknown() {
-		modifyReq.NewPassword = valueStringPtr(data.PasswordWo)
-		hasModifications = true
-	} else if !data.Password.IsNull() && !data.Password.IsUnknown() {
-		modifyReq.NewPassword = valueStringPtr(data.Password)
-		hasModifications = true
-	}
-
-	// Handle other settings (except 2FA for now)
-	if !data.EnableSearchIndexing.IsNull() {
-		modifyReq.EnableSearchIndexing = valueBoolPtr(data.EnableSearchIndexing)
-		hasModifications = true
-	}
-
-	// Apply initial modifications if any
-	if hasModifications {
-		modifyResp, err := r.client.ModifyUser(ctx, modifyReq)
+	adopted := false
+	if data.AdoptExisting.ValueBool() {
+		existing := data
+		found, err := r.readUser(ctx, &existing)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to modify user: %s", err))
+			resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for existing user: %s", err))
 			return
 		}
-		defer modifyResp.Body.Close()
 
-		if modifyResp.StatusCode != http.StatusOK {
-			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to modify user (status: %d)", modifyResp.StatusCode))
-			return
+		if found {
+			resp.Diagnostics.Append(r.reconcileUser(ctx, &data, &existing)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			resp.Diagnostics.AddWarning(
+				"Existing User Adopted",
+				fmt.Sprintf("User %s already existed and was adopted. Its settings and password reset methods were changed to match the configuration.", data.UserName.ValueString()),
+			)
+			adopted = true
 		}
 	}
 
-	// Step 2: Upsert password reset methods if configured
-	if !data.PasswordResetMethods.IsNull() && !data.PasswordResetMethods.IsUnknown() {
-		var methods []PasswordResetMethodModel
-		diags := data.PasswordResetMethods.ElementsAs(ctx, &methods, false)
+	if !adopted {
+		methods, diags := passwordResetMethodList(ctx, data.PasswordResetMethods)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 
-		for _, method := range methods {
-			upsertReq := api.UpsertPasswordResetRequest{
-				UserName: data.UserName.ValueString(),
-				Type:     method.Type.ValueString(),
-				Target:   method.Target.ValueString(),
-			}
-
-			if !method.Description.IsNull() && !method.Description.IsUnknown() {
-				desc := method.Description.ValueString()
-				upsertReq.Description = &desc
-			}
-
-			if !method.AllowMfaReset.IsNull() && !method.AllowMfaReset.IsUnknown() {
-				allow := method.AllowMfaReset.ValueBool()
-				upsertReq.AllowMfaReset = &allow
-			}
-
-			upsertResp, err := r.client.CreateOrUpdatePasswordResetMethod(ctx, upsertReq)
-			if err != nil {
-				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create password reset method: %s", err))
-				return
-			}
-			defer upsertResp.Body.Close()
+		// Create the user with as many settings as createUser accepts
+		createReq, included := createUserRequest(&data, methods)
+		basic, diags := r.createUser(ctx, createReq)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-			if upsertResp.StatusCode != http.StatusOK {
-				resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to create password reset method (status: %d)", upsertResp.StatusCode))
+		steps := r.userCreateSteps(&data, methods, included, basic)
+		for i, step := range steps {
+			setStep(ctx, step.name)
+			stepDiags := step.run(ctx)
+			resp.Diagnostics.Append(stepDiags...)
+			if stepDiags.HasError() {
+				r.recoverPartialCreate(ctx, &data, steps[i:], resp)
 				return
 			}
 		}
 	}
 
-	// Step 3: Enable 2FA if requested (after password reset methods are configured)
-	if !data.RequireTwoFactorAuthentication.IsNull() && data.RequireTwoFactorAuthentication.ValueBool() {
-		enable2FAReq := api.ModifyUserJSONRequestBody{
-			UserName:                       data.UserName.ValueString(),
-			RequireTwoFactorAuthentication: valueBoolPtr(data.RequireTwoFactorAuthentication),
-		}
-
-		enable2FAResp, err := r.client.ModifyUser(ctx, enable2FAReq)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable 2FA: %s", err))
-			return
-		}
-		defer enable2FAResp.Body.Close()
-
-		if enable2FAResp.StatusCode != http.StatusOK {
-			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to enable 2FA (status: %d)", enable2FAResp.StatusCode))
-			return
-		}
-	}
+	data.Id = data.UserName.StringValue
 
-	data.Id = data.UserName
+	resp.Diagnostics.Append(setPasswordHash(&data)...)
 
 	// Read back the user to get current state
 	if _, err := r.readUser(ctx, &data); err != nil {
 		resp.Diagnostics.AddWarning("Read Warning", fmt.Sprintf("Created user but unable to read back: %s", err))
 	}
 
-	// Clear write-only fields (password_wo is write-only and should not be stored)
+	// Clear write-only fields (password and password_wo are write-only and should not be stored)
+	data.Password = types.StringNull()
 	data.PasswordWo = types.StringNull()
-	data.NewUserName = types.StringNull()
+	data.NewUserName = NewMailboxNameNull()
 
 	tflog.Trace(ctx, "created purelymail_user resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userIdentity(&data))...)
 }
 
 func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -287,6 +413,13 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx, finish, diags := startOperation(ctx, "read", data.Timeouts.Read, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
 	// Read user data including password reset methods
 	found, err := r.readUser(ctx, &data)
 	if err != nil {
@@ -300,183 +433,812 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// Clear write-only fields (password_wo is write-only and should not be stored)
+	// Clear write-only fields (password and password_wo are write-only and should not be stored)
+	data.Password = types.StringNull()
 	data.PasswordWo = types.StringNull()
-	data.NewUserName = types.StringNull()
+	data.NewUserName = NewMailboxNameNull()
+
+	setImportedUserDefaults(&data)
 
 	tflog.Trace(ctx, "read purelymail_user resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userIdentity(&data))...)
 }
 
 func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data UserResourceModel
 	var state UserResourceModel
 
+	var password, passwordWo types.String
+
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &passwordWo)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Step 1: Disable 2FA first if it's being turned off (before modifying password reset methods)
-	if !state.RequireTwoFactorAuthentication.IsNull() && state.RequireTwoFactorAuthentication.ValueBool() &&
-		(!data.RequireTwoFactorAuthentication.IsNull() && !data.RequireTwoFactorAuthentication.ValueBool()) {
-
-		disable2FAReq := api.ModifyUserJSONRequestBody{
-			UserName:                       state.UserName.ValueString(),
-			RequireTwoFactorAuthentication: valueBoolPtr(data.RequireTwoFactorAuthentication),
-		}
+	ctx, finish, diags := startOperation(ctx, "update", data.Timeouts.Update, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
 
-		disable2FAResp, err := r.client.ModifyUser(ctx, disable2FAReq)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable 2FA: %s", err))
-			return
-		}
-		defer disable2FAResp.Body.Close()
+	keepPriorSpelling(&data.UserName, state.UserName)
 
-		if disable2FAResp.StatusCode != http.StatusOK {
-			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to disable 2FA (status: %d)", disable2FAResp.StatusCode))
-			return
-		}
+	// Only send the password if it doesn't match the stored hash, see ModifyPlan
+	passwordChanged := data.PasswordHash.IsUnknown()
+	if passwordChanged {
+		data.Password = password
 	}
 
-	// Step 2: Update basic user settings (username, password, search indexing)
-	modifyReq := api.ModifyUserJSONRequestBody{
-		UserName: state.UserName.ValueString(),
+	// Only send password_wo if its version changed
+	if !data.PasswordWoVersion.Equal(state.PasswordWoVersion) {
+		data.PasswordWo = passwordWo
 	}
-	hasModifications := false
 
-	// Handle username change
-	if !data.NewUserName.IsNull() && data.NewUserName.ValueString() != state.UserName.ValueString() {
-		modifyReq.NewUserName = valueStringPtr(data.NewUserName)
-		hasModifications = true
+	resp.Diagnostics.Append(r.reconcileUser(ctx, &data, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Handle password update (prefer password_wo if both are set)
-	if !data.PasswordWo.IsNull() && !data.PasswordWo.IsUnknown() {
-		modifyReq.NewPassword = valueStringPtr(data.PasswordWo)
-		hasModifications = true
-	} else if !data.Password.IsNull() && !data.Password.IsUnknown() {
-		modifyReq.NewPassword = valueStringPtr(data.Password)
-		hasModifications = true
+	if passwordChanged {
+		resp.Diagnostics.Append(setPasswordHash(&data)...)
 	}
 
-	// Handle search indexing
-	if !data.EnableSearchIndexing.IsNull() {
-		modifyReq.EnableSearchIndexing = valueBoolPtr(data.EnableSearchIndexing)
-		hasModifications = true
+	// Read back the user to get current state
+	if _, err := r.readUser(ctx, &data); err != nil {
+		resp.Diagnostics.AddWarning("Read Warning", fmt.Sprintf("Updated user but unable to read back: %s", err))
 	}
 
-	if hasModifications {
-		httpResp, err := r.client.ModifyUser(ctx, modifyReq)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to modify user: %s", err))
-			return
-		}
-		defer httpResp.Body.Close()
+	// Clear write-only fields (password and password_wo are write-only and should not be stored)
+	data.Password = types.StringNull()
+	data.PasswordWo = types.StringNull()
+	data.NewUserName = NewMailboxNameNull()
 
-		if httpResp.StatusCode != http.StatusOK {
-			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to modify user (status: %d)", httpResp.StatusCode))
-			return
-		}
+	tflog.Trace(ctx, "updated purelymail_user resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userIdentity(&data))...)
+}
+
+func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data UserResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Update username if it changed
-	if !data.NewUserName.IsNull() && data.NewUserName.ValueString() != state.UserName.ValueString() {
-		data.UserName = data.NewUserName
-		data.Id = data.NewUserName
-	} else {
-		data.Id = data.UserName
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	defer finish(&resp.Diagnostics)
 
-	// Step 3: Update password reset methods
-	// Get current methods from state
-	var stateMethods []PasswordResetMethodModel
-	if !state.PasswordResetMethods.IsNull() && !state.PasswordResetMethods.IsUnknown() {
-		diags := state.PasswordResetMethods.ElementsAs(ctx, &stateMethods, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Deletion Protection Enabled",
+			fmt.Sprintf("User %s has deletion_protection enabled. Set deletion_protection = false and apply before destroying or replacing it.", data.UserName.ValueString()),
+		)
+		return
 	}
 
-	// Get desired methods from plan
-	var planMethods []PasswordResetMethodModel
-	if !data.PasswordResetMethods.IsNull() && !data.PasswordResetMethods.IsUnknown() {
-		diags := data.PasswordResetMethods.ElementsAs(ctx, &planMethods, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	// Note: Password reset methods are automatically deleted when the user is deleted
+	// No need to explicitly delete them
+
+	if err := r.deleteUser(ctx, data.UserName.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete user: %s", err))
+		return
 	}
 
-	// Delete methods that are no longer in the plan
-	stateTargets := make(map[string]bool)
-	for _, method := range stateMethods {
-		stateTargets[method.Target.ValueString()] = true
+	if !data.OnDestroyForwardTo.IsNull() {
+		r.forwardDeletedUser(ctx, &data, resp)
 	}
 
-	planTargets := make(map[string]bool)
-	for _, method := range planMethods {
-		planTargets[method.Target.ValueString()] = true
-	}
-
-	for _, method := range stateMethods {
-		target := method.Target.ValueString()
-		if !planTargets[target] {
-			// Delete this method
-			delResp, err := r.client.DeletePasswordResetMethod(ctx, api.DeletePasswordResetRequest{
-				UserName: data.UserName.ValueString(),
-				Target:   target,
-			})
-			if err != nil {
-				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete password reset method: %s", err))
-				return
-			}
-			defer delResp.Body.Close()
+	tflog.Trace(ctx, "deleted purelymail_user resource")
+}
 
-			if delResp.StatusCode != http.StatusOK {
-				resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete password reset method (status: %d)", delResp.StatusCode))
-				return
-			}
-		}
+func (r *UserResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("password"),
+			path.MatchRoot("password_wo"),
+		),
 	}
+}
 
-	// Upsert methods in the plan
-	for _, method := range planMethods {
-		upsertReq := api.UpsertPasswordResetRequest{
-			UserName: data.UserName.ValueString(),
-			Type:     method.Type.ValueString(),
-			Target:   method.Target.ValueString(),
-		}
+func (r *UserResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data UserResourceModel
 
-		if !method.Description.IsNull() && !method.Description.IsUnknown() {
-			desc := method.Description.ValueString()
-			upsertReq.Description = &desc
-		}
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		if !method.AllowMfaReset.IsNull() && !method.AllowMfaReset.IsUnknown() {
-			allow := method.AllowMfaReset.ValueBool()
-			upsertReq.AllowMfaReset = &allow
-		}
+	if !data.NewUserName.IsNull() && !data.NewUserName.IsUnknown() && !data.UserName.IsUnknown() &&
+		semanticallyEqual(normalizeMailboxName, data.NewUserName.ValueString(), data.UserName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("new_user_name"),
+			"Invalid Rename",
+			"new_user_name must differ from user_name.",
+		)
+	}
 
-		upsertResp, err := r.client.CreateOrUpdatePasswordResetMethod(ctx, upsertReq)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to upsert password reset method: %s", err))
-			return
+	methods, diags := passwordResetMethodList(ctx, data.PasswordResetMethods)
+	resp.Diagnostics.Append(diags...)
+	targets := make(map[string]string)
+	for _, method := range methods {
+		targetPath := path.Root("password_reset_methods").AtMapKey(method.Target)
+		resp.Diagnostics.Append(validatePasswordResetTarget(targetPath, method.Type, NewPasswordResetTargetValue(method.Target))...)
+
+		// Keys that differ only in spelling would be the same method
+		target := passwordResetMethodTarget(method)
+		if other, ok := targets[target]; ok {
+			resp.Diagnostics.AddAttributeError(
+				targetPath,
+				"Duplicate Password Reset Method",
+				fmt.Sprintf("Password reset methods %q and %q have the same target.", other, method.Target),
+			)
 		}
-		defer upsertResp.Body.Close()
+		targets[target] = method.Target
+	}
 
-		if upsertResp.StatusCode != http.StatusOK {
-			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to upsert password reset method (status: %d)", upsertResp.StatusCode))
-			return
+	var forwardTo []EmailAddressValue
+	if !data.OnDestroyForwardTo.IsUnknown() {
+		resp.Diagnostics.Append(data.OnDestroyForwardTo.ElementsAs(ctx, &forwardTo, true)...)
+	}
+	if len(forwardTo) > 0 && !data.UserName.IsNull() && !data.UserName.IsUnknown() {
+		if _, ok := emailDomain(data.UserName.ValueString()); !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("on_destroy_forward_to"),
+				"Invalid Attribute Combination",
+				"on_destroy_forward_to requires user_name to be a full email address, as the routing rule is created for its domain.",
+			)
+		}
+	}
+	for i, target := range forwardTo {
+		if !target.IsNull() && !target.IsUnknown() && !data.UserName.IsUnknown() &&
+			semanticallyEqual(normalizeMailboxName, target.ValueString(), data.UserName.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("on_destroy_forward_to").AtListIndex(i),
+				"Invalid Forwarding Target",
+				"on_destroy_forward_to can't contain user_name, mail to the deleted user would be forwarded to itself.",
+			)
 		}
 	}
 
-	// Step 4: Enable 2FA if it's being turned on (after password reset methods are configured)
-	if (!state.RequireTwoFactorAuthentication.IsNull() && !state.RequireTwoFactorAuthentication.ValueBool() &&
-		!data.RequireTwoFactorAuthentication.IsNull() && data.RequireTwoFactorAuthentication.ValueBool()) ||
+	if data.PasswordResetMethodsMode.ValueString() == passwordResetMethodsIgnore && !data.PasswordResetMethods.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password_reset_methods"),
+			"Invalid Attribute Combination",
+			"password_reset_methods can't be set when password_reset_methods_mode is \"ignore\". Use \"additive\" to manage "+
+				"some of the user's password reset methods here and others with purelymail_password_reset_method resources.",
+		)
+	}
+
+	// Purelymail refuses to enable 2FA for users without a way to reset it.
+	// Other modes may leave methods on the user that aren't configured here.
+	if data.RequireTwoFactorAuthentication.ValueBool() && !data.PasswordResetMethodsMode.IsUnknown() &&
+		passwordResetMethodsMode(&data) == passwordResetMethodsAuthoritative &&
+		!data.PasswordResetMethods.IsUnknown() && len(data.PasswordResetMethods.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("require_two_factor_authentication"),
+			"Missing Password Reset Method",
+			"Two-factor authentication requires at least one password reset method. Configure password_reset_methods, "+
+				"or set password_reset_methods_mode = \"additive\" if they are managed with purelymail_password_reset_method resources.",
+		)
+	}
+}
+
+func (r *UserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan UserResourceModel
+	var password types.String
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		if password.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("password_hash"), types.StringNull())...)
+		}
+		resp.Diagnostics.Append(warnUnknownUserDomain(ctx, r.client, path.Root("user_name"), plan.UserName.StringValue)...)
+		r.warnAdoptExisting(ctx, &plan, resp)
+		return
+	}
+
+	var state UserResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.UserName.Equal(state.UserName) {
+		resp.Diagnostics.Append(warnUnknownUserDomain(ctx, r.client, path.Root("user_name"), plan.UserName.StringValue)...)
+	}
+
+	// Replacing the user deletes it like a destroy, which forwards its mail
+	if !state.OnDestroyForwardTo.IsNull() &&
+		(plan.UserName.IsUnknown() || !semanticallyEqual(normalizeMailboxName, plan.UserName.ValueString(), state.UserName.ValueString())) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("user_name"),
+			"Mail Will Be Forwarded",
+			fmt.Sprintf("Changing user_name replaces user %s, and deleting it creates a routing rule that forwards its mail as set by on_destroy_forward_to. "+
+				"Remove on_destroy_forward_to and apply first if mail to %s shouldn't be forwarded.",
+				state.UserName.ValueString(), state.UserName.ValueString()),
+		)
+	}
+
+	// Plan a password change only if the configured password doesn't match the stored hash
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("password_hash"), planPasswordHash(password, state.PasswordHash))...)
+
+	resp.Diagnostics.Append(r.validateRename(ctx, &plan)...)
+}
+
+// validateRename checks that new_user_name is on one of the account's domains.
+func (r *UserResource) validateRename(ctx context.Context, plan *UserResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.NewUserName.IsNull() || plan.NewUserName.IsUnknown() || r.client == nil {
+		return diags
+	}
+
+	// User names without a domain part can't be checked
+	domainName, ok := emailDomain(plan.NewUserName.ValueString())
+	if !ok {
+		return diags
+	}
+
+	found, suggestion, err := lookupAccountDomain(ctx, r.client, domainName)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to check the domain of new_user_name: %s", err))
+		return diags
+	}
+	if !found {
+		diags.AddAttributeError(
+			path.Root("new_user_name"),
+			"Unknown Domain",
+			unknownDomainMessage("new_user_name", domainName, suggestion),
+		)
+	}
+
+	return diags
+}
+
+// warnAdoptExisting warns when creating the user will adopt an existing one.
+func (r *UserResource) warnAdoptExisting(ctx context.Context, plan *UserResourceModel, resp *resource.ModifyPlanResponse) {
+	if !plan.AdoptExisting.ValueBool() || plan.UserName.IsUnknown() || r.client == nil {
+		return
+	}
+
+	existing := *plan
+	found, err := r.readUser(ctx, &existing)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to check for existing user: %s", err))
+		return
+	}
+	if found {
+		resp.Diagnostics.AddWarning(
+			"Existing User Will Be Adopted",
+			fmt.Sprintf("User %s already exists. It will be adopted instead of created, and its settings and password reset methods will be changed to match the configuration.", plan.UserName.ValueString()),
+		)
+	}
+}
+
+func (r *UserResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored password in plaintext, replace it with its hash
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeUserState(req, resp, upgradeUserPassword, upgradeUserPasswordResetMethods)
+			},
+		},
+		// Version 1 stored password_reset_methods as a list
+		1: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeUserState(req, resp, upgradeUserPasswordResetMethods)
+			},
+		},
+	}
+}
+
+// upgradeUserState applies upgrades to the raw JSON of a prior
+// purelymail_user state, in order.
+func upgradeUserState(req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse, upgrades ...func(map[string]json.RawMessage) error) {
+	var prior map[string]json.RawMessage
+	if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to decode prior purelymail_user state: %s", err))
+		return
+	}
+
+	for _, upgrade := range upgrades {
+		if err := upgrade(prior); err != nil {
+			resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to upgrade prior purelymail_user state: %s", err))
+			return
+		}
+	}
+
+	upgraded, err := json.Marshal(prior)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to encode upgraded purelymail_user state: %s", err))
+		return
+	}
+
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+// upgradeUserPassword replaces the plaintext password of a version 0 state
+// with its hash.
+func upgradeUserPassword(prior map[string]json.RawMessage) error {
+	var password *string
+	if err := json.Unmarshal(prior["password"], &password); len(prior["password"]) > 0 && err != nil {
+		return fmt.Errorf("unable to decode password: %w", err)
+	}
+
+	prior["password"] = json.RawMessage("null")
+	prior["password_hash"] = json.RawMessage("null")
+	if password != nil {
+		hash, err := hashPassword(*password)
+		if err != nil {
+			return fmt.Errorf("unable to hash password: %w", err)
+		}
+		prior["password_hash"], _ = json.Marshal(hash)
+	}
+
+	return nil
+}
+
+// upgradeUserPasswordResetMethods converts the password_reset_methods list of
+// version 0 and 1 states to a map keyed by target.
+func upgradeUserPasswordResetMethods(prior map[string]json.RawMessage) error {
+	var methods []map[string]json.RawMessage
+	if err := json.Unmarshal(prior["password_reset_methods"], &methods); len(prior["password_reset_methods"]) > 0 && err != nil {
+		return fmt.Errorf("unable to decode password_reset_methods: %w", err)
+	}
+
+	if methods == nil {
+		prior["password_reset_methods"] = json.RawMessage("null")
+		return nil
+	}
+
+	byTarget := make(map[string]map[string]json.RawMessage, len(methods))
+	for _, method := range methods {
+		var target string
+		if err := json.Unmarshal(method["target"], &target); err != nil {
+			return fmt.Errorf("unable to decode password reset method target: %w", err)
+		}
+		delete(method, "target")
+		byTarget[target] = method
+	}
+
+	encoded, err := json.Marshal(byTarget)
+	if err != nil {
+		return fmt.Errorf("unable to encode password_reset_methods: %w", err)
+	}
+	prior["password_reset_methods"] = encoded
+
+	return nil
+}
+
+func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Use the username as the import identifier
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("user_name"), path.Root("user_name"), req, resp)
+}
+
+// userCreateStep is one of the API calls that finish creating a user.
+type userCreateStep struct {
+	name string
+	run  func(ctx context.Context) diag.Diagnostics
+}
+
+// createUserRequest builds a createUser request with the password, search
+// indexing and up to one email and one phone password reset method, so most
+// users are created in a single call. It also returns which methods it
+// includes. createUser can't set allow_mfa_reset, so methods that allow MFA
+// resets are left out.
+func createUserRequest(data *UserResourceModel, methods []PasswordResetMethodModel) (api.CreateUserRequest, []bool) {
+	createReq := api.CreateUserRequest{
+		UserName: data.UserName.ValueString(),
+	}
+
+	if !data.PasswordWo.IsNull() && !data.PasswordWo.IsUnknown() {
+		createReq.Password = valueStringPtr(data.PasswordWo)
+	} else {
+		createReq.Password = valueStringPtr(data.Password)
+	}
+	createReq.EnableSearchIndexing = valueBoolPtr(data.EnableSearchIndexing)
+
+	included := make([]bool, len(methods))
+	for i, method := range methods {
+		if method.AllowMfaReset.ValueBool() {
+			continue
+		}
+
+		target := passwordResetMethodTarget(method)
+		switch api.UserPasswordResetMethodType(method.Type.ValueString()) {
+		case api.Email:
+			if createReq.RecoveryEmail == nil {
+				createReq.RecoveryEmail = &target
+				createReq.RecoveryEmailDescription = valueStringPtr(method.Description)
+				included[i] = true
+			}
+		case api.Phone:
+			if createReq.RecoveryPhone == nil {
+				createReq.RecoveryPhone = &target
+				createReq.RecoveryPhoneDescription = valueStringPtr(method.Description)
+				included[i] = true
+			}
+		}
+	}
+
+	// Recovery targets are only added with password reset enabled
+	if createReq.RecoveryEmail != nil || createReq.RecoveryPhone != nil {
+		enable := true
+		createReq.EnablePasswordReset = &enable
+	}
+
+	return createReq, included
+}
+
+// createUser sends the createUser request. If the API rejects the settings
+// fields in it, the user is created with only its name instead and basic is true, so
+// the settings have to be applied with ModifyUser and
+// CreateOrUpdatePasswordResetMethod afterwards.
+func (r *UserResource) createUser(ctx context.Context, createReq api.CreateUserRequest) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	setStep(ctx, "create user")
+	httpResp, err := r.client.CreateUser(ctx, createReq)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to create user: %s", err))
+		return false, diags
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusOK {
+		return false, diags
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(httpResp.Body, 4096))
+	message := strings.TrimSpace(string(body))
+	failed := fmt.Sprintf("Failed to create user (status: %d)", httpResp.StatusCode)
+	if message != "" {
+		failed += ": " + message
+	}
+
+	basicReq := api.CreateUserRequest{UserName: createReq.UserName}
+	if httpResp.StatusCode != http.StatusBadRequest || createReq == basicReq || !rejectsCreateUserFields(message) {
+		diags.AddError("API Error", failed)
+		return false, diags
+	}
+
+	tflog.Debug(ctx, "createUser rejected settings, retrying with the user name only", map[string]interface{}{
+		"user_name": createReq.UserName,
+		"error":     message,
+	})
+
+	basicResp, err := r.client.CreateUser(ctx, basicReq)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to create user: %s", err))
+		return true, diags
+	}
+	defer basicResp.Body.Close()
+
+	// The original error explains more than the failed fallback
+	if basicResp.StatusCode != http.StatusOK {
+		diags.AddError("API Error", failed)
+	}
+	return true, diags
+}
+
+// createUserSettingsFields are the fields of the extended createUser request
+// besides the user name and password, which older API versions reject.
+var createUserSettingsFields = []string{
+	"enablePasswordReset",
+	"enableSearchIndexing",
+	"recoveryEmail",
+	"recoveryPhone",
+	"sendWelcomeEmail",
+}
+
+// rejectsCreateUserFields reports whether a createUser error rejects the
+// fields of the extended request, rather than the user name or password.
+func rejectsCreateUserFields(message string) bool {
+	lower := strings.ToLower(message)
+	if strings.Contains(lower, "unknown field") || strings.Contains(lower, "unrecognized field") {
+		return true
+	}
+
+	for _, field := range createUserSettingsFields {
+		if strings.Contains(lower, strings.ToLower(field)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// userCreateSteps returns the API calls that finish creating the user, in the
+// order the API requires. Settings and password reset methods already sent
+// with createUser are skipped unless it fell back to the basic request.
+func (r *UserResource) userCreateSteps(data *UserResourceModel, methods []PasswordResetMethodModel, included []bool, basic bool) []userCreateStep {
+	var steps []userCreateStep
+	userName := data.UserName.ValueString()
+
+	// Build modify request for initial settings (without 2FA)
+	modifyReq := api.ModifyUserJSONRequestBody{
+		UserName: userName,
+	}
+	hasModifications := false
+
+	// Handle password (prefer password_wo if both are set)
+	if !data.PasswordWo.IsNull() && !data.PasswordWo.IsUnknown() {
+		modifyReq.NewPassword = valueStringPtr(data.PasswordWo)
+		hasModifications = true
+	} else if !data.Password.IsNull() && !data.Password.IsUnknown() {
+		modifyReq.NewPassword = valueStringPtr(data.Password)
+		hasModifications = true
+	}
+
+	// Handle other settings (except 2FA for now)
+	if !data.EnableSearchIndexing.IsNull() && !data.EnableSearchIndexing.IsUnknown() {
+		modifyReq.EnableSearchIndexing = valueBoolPtr(data.EnableSearchIndexing)
+		hasModifications = true
+	}
+
+	// Apply initial modifications if createUser couldn't
+	if basic && hasModifications {
+		steps = append(steps, userCreateStep{
+			name: "set password and search indexing",
+			run: func(ctx context.Context) diag.Diagnostics {
+				var diags diag.Diagnostics
+
+				modifyResp, err := r.client.ModifyUser(ctx, modifyReq)
+				if err != nil {
+					diags.AddError("Client Error", fmt.Sprintf("Unable to modify user: %s", err))
+					return diags
+				}
+				defer modifyResp.Body.Close()
+
+				if modifyResp.StatusCode != http.StatusOK {
+					diags.AddError("API Error", fmt.Sprintf("Failed to modify user (status: %d)", modifyResp.StatusCode))
+				}
+				return diags
+			},
+		})
+	}
+
+	// Upsert the password reset methods createUser didn't add
+	for i, method := range methods {
+		if included[i] && !basic {
+			continue
+		}
+
+		upsertReq := api.UpsertPasswordResetRequest{
+			UserName: userName,
+			Type:     api.UserPasswordResetMethodType(method.Type.ValueString()),
+			Target:   passwordResetMethodTarget(method),
+		}
+
+		if !method.Description.IsNull() && !method.Description.IsUnknown() {
+			desc := method.Description.ValueString()
+			upsertReq.Description = &desc
+		}
+
+		if !method.AllowMfaReset.IsNull() && !method.AllowMfaReset.IsUnknown() {
+			allow := method.AllowMfaReset.ValueBool()
+			upsertReq.AllowMfaReset = &allow
+		}
+
+		steps = append(steps, userCreateStep{
+			name: fmt.Sprintf("add password reset method %s", upsertReq.Target),
+			run: func(ctx context.Context) diag.Diagnostics {
+				var diags diag.Diagnostics
+
+				upsertResp, err := r.client.CreateOrUpdatePasswordResetMethod(ctx, upsertReq)
+				if err != nil {
+					diags.AddError("Client Error", fmt.Sprintf("Unable to create password reset method: %s", err))
+					return diags
+				}
+				defer upsertResp.Body.Close()
+
+				if upsertResp.StatusCode != http.StatusOK {
+					diags.AddError("API Error", fmt.Sprintf("Failed to create password reset method (status: %d)", upsertResp.StatusCode))
+				}
+				return diags
+			},
+		})
+	}
+
+	// Enable 2FA if requested (after password reset methods are configured)
+	if !data.RequireTwoFactorAuthentication.IsNull() && data.RequireTwoFactorAuthentication.ValueBool() {
+		enable2FAReq := api.ModifyUserJSONRequestBody{
+			UserName:                       userName,
+			RequireTwoFactorAuthentication: valueBoolPtr(data.RequireTwoFactorAuthentication),
+		}
+
+		steps = append(steps, userCreateStep{
+			name: "enable two-factor authentication",
+			run: func(ctx context.Context) diag.Diagnostics {
+				var diags diag.Diagnostics
+
+				enable2FAResp, err := r.client.ModifyUser(ctx, enable2FAReq)
+				if err != nil {
+					diags.AddError("Client Error", fmt.Sprintf("Unable to enable 2FA: %s", err))
+					return diags
+				}
+				defer enable2FAResp.Body.Close()
+
+				if enable2FAResp.StatusCode != http.StatusOK {
+					diags.AddError("API Error", fmt.Sprintf("Failed to enable 2FA (status: %d)", enable2FAResp.StatusCode))
+				}
+				return diags
+			},
+		})
+	}
+
+	return steps
+}
+
+// recoverPartialCreate handles a failed create step after the user itself was
+// created. remaining starts with the step that failed. Depending on
+// on_create_failure the user is deleted again, or saved to state so Terraform
+// marks it as tainted and replaces it on the next apply.
+func (r *UserResource) recoverPartialCreate(ctx context.Context, data *UserResourceModel, remaining []userCreateStep, resp *resource.CreateResponse) {
+	userName := data.UserName.ValueString()
+
+	// The step may have failed because the create timeout expired
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	if data.OnCreateFailure.ValueString() == onCreateFailureRollback {
+		err := r.deleteUser(ctx, userName)
+		if err == nil {
+			resp.Diagnostics.AddError(
+				"User Creation Rolled Back",
+				fmt.Sprintf("Creating user %s failed at step %q, so the user was deleted again.", userName, remaining[0].name),
+			)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Rollback Failed",
+			fmt.Sprintf("Creating user %s failed at step %q, and deleting it again failed: %s. The user is saved to state instead.", userName, remaining[0].name, err),
+		)
+	}
+
+	unfinished := make([]string, len(remaining))
+	for i, step := range remaining {
+		unfinished[i] = step.name
+	}
+
+	data.Id = data.UserName.StringValue
+
+	// Save what was actually applied
+	if _, err := r.readUser(ctx, data); err != nil {
+		resp.Diagnostics.AddWarning("Read Warning", fmt.Sprintf("Partially created user but unable to read back: %s", err))
+	}
+
+	// The password may not have been set
+	data.PasswordHash = types.StringNull()
+
+	// Clear write-only fields (password and password_wo are write-only and should not be stored)
+	data.Password = types.StringNull()
+	data.PasswordWo = types.StringNull()
+	data.NewUserName = NewMailboxNameNull()
+
+	resp.Diagnostics.AddError(
+		"User Partially Created",
+		fmt.Sprintf("User %s was created, but these steps did not finish:\n  - %s\nIt was saved to state as tainted and will be replaced on the next apply.",
+			userName, strings.Join(unfinished, "\n  - ")),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userIdentity(data))...)
+}
+
+// reconcileUser changes an existing user from state to match data. It is used
+// by Update and when Create adopts an existing user.
+func (r *UserResource) reconcileUser(ctx context.Context, data *UserResourceModel, state *UserResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Step 1: Disable 2FA first if it's being turned off (before modifying password reset methods)
+	if !state.RequireTwoFactorAuthentication.IsNull() && state.RequireTwoFactorAuthentication.ValueBool() &&
+		(!data.RequireTwoFactorAuthentication.IsNull() && !data.RequireTwoFactorAuthentication.IsUnknown() &&
+			!data.RequireTwoFactorAuthentication.ValueBool()) {
+
+		disable2FAReq := api.ModifyUserJSONRequestBody{
+			UserName:                       state.UserName.ValueString(),
+			RequireTwoFactorAuthentication: valueBoolPtr(data.RequireTwoFactorAuthentication),
+		}
+
+		setStep(ctx, "disable two-factor authentication")
+		disable2FAResp, err := r.client.ModifyUser(ctx, disable2FAReq)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to disable 2FA: %s", err))
+			return diags
+		}
+		defer disable2FAResp.Body.Close()
+
+		if disable2FAResp.StatusCode != http.StatusOK {
+			diags.AddError("API Error", fmt.Sprintf("Failed to disable 2FA (status: %d)", disable2FAResp.StatusCode))
+			return diags
+		}
+	}
+
+	// Step 2: Update basic user settings (username, password, search indexing)
+	modifyReq := api.ModifyUserJSONRequestBody{
+		UserName: state.UserName.ValueString(),
+	}
+	hasModifications := false
+
+	// Handle username change
+	if !data.NewUserName.IsNull() && data.NewUserName.ValueString() != state.UserName.ValueString() {
+		modifyReq.NewUserName = valueStringPtr(data.NewUserName.StringValue)
+		hasModifications = true
+	}
+
+	// Handle password update (prefer password_wo if both are set)
+	if !data.PasswordWo.IsNull() && !data.PasswordWo.IsUnknown() {
+		modifyReq.NewPassword = valueStringPtr(data.PasswordWo)
+		hasModifications = true
+	} else if !data.Password.IsNull() && !data.Password.IsUnknown() {
+		modifyReq.NewPassword = valueStringPtr(data.Password)
+		hasModifications = true
+	}
+
+	// Handle search indexing
+	if !data.EnableSearchIndexing.IsNull() {
+		modifyReq.EnableSearchIndexing = valueBoolPtr(data.EnableSearchIndexing)
+		hasModifications = true
+	}
+
+	if hasModifications {
+		setStep(ctx, "modify user")
+		httpResp, err := r.client.ModifyUser(ctx, modifyReq)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to modify user: %s", err))
+			return diags
+		}
+		defer httpResp.Body.Close()
+
+		if httpResp.StatusCode != http.StatusOK {
+			diags.AddError("API Error", fmt.Sprintf("Failed to modify user (status: %d)", httpResp.StatusCode))
+			return diags
+		}
+	}
+
+	// Update username if it changed
+	if !data.NewUserName.IsNull() && data.NewUserName.ValueString() != state.UserName.ValueString() {
+		data.UserName = data.NewUserName
+		data.Id = data.NewUserName.StringValue
+	} else {
+		data.Id = data.UserName.StringValue
+	}
+
+	// Step 3: Update password reset methods, unless they are ignored
+	if passwordResetMethodsMode(data) != passwordResetMethodsIgnore {
+		diags.Append(r.reconcilePasswordResetMethods(ctx, data, state)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	// Step 4: Enable 2FA if it's being turned on (after password reset methods are configured)
+	if (!state.RequireTwoFactorAuthentication.IsNull() && !state.RequireTwoFactorAuthentication.ValueBool() &&
+		!data.RequireTwoFactorAuthentication.IsNull() && data.RequireTwoFactorAuthentication.ValueBool()) ||
 		(state.RequireTwoFactorAuthentication.IsNull() &&
 			!data.RequireTwoFactorAuthentication.IsNull() && data.RequireTwoFactorAuthentication.ValueBool()) {
 
@@ -485,64 +1247,317 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 			RequireTwoFactorAuthentication: valueBoolPtr(data.RequireTwoFactorAuthentication),
 		}
 
+		setStep(ctx, "enable two-factor authentication")
 		enable2FAResp, err := r.client.ModifyUser(ctx, enable2FAReq)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable 2FA: %s", err))
-			return
+			diags.AddError("Client Error", fmt.Sprintf("Unable to enable 2FA: %s", err))
+			return diags
 		}
 		defer enable2FAResp.Body.Close()
 
 		if enable2FAResp.StatusCode != http.StatusOK {
-			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to enable 2FA (status: %d)", enable2FAResp.StatusCode))
-			return
+			diags.AddError("API Error", fmt.Sprintf("Failed to enable 2FA (status: %d)", enable2FAResp.StatusCode))
+			return diags
 		}
 	}
 
-	// Read back the user to get current state
-	if _, err := r.readUser(ctx, &data); err != nil {
-		resp.Diagnostics.AddWarning("Read Warning", fmt.Sprintf("Updated user but unable to read back: %s", err))
+	return diags
+}
+
+// reconcilePasswordResetMethods deletes, adds and updates the user's password
+// reset methods to match the plan. The plan is compared with the methods the
+// user has now, so methods that already exist as planned, like ones that were
+// managed by purelymail_password_reset_method resources before, are left
+// alone. In additive mode only methods that were already managed in the prior
+// state are deleted, and when switching to additive mode none are.
+func (r *UserResource) reconcilePasswordResetMethods(ctx context.Context, data *UserResourceModel, state *UserResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	currentMethods, err := listPasswordResetMethods(ctx, r.client, state.UserName.ValueString())
+	if err != nil {
+		diags.AddError("Read Error", fmt.Sprintf("Unable to read password reset methods: %s", err))
+		return diags
 	}
 
-	// Clear write-only fields (password_wo is write-only and should not be stored)
-	data.PasswordWo = types.StringNull()
-	data.NewUserName = types.StringNull()
+	// Get desired methods from plan
+	planMethods, diags := passwordResetMethodList(ctx, data.PasswordResetMethods)
+	if diags.HasError() {
+		return diags
+	}
 
-	tflog.Trace(ctx, "updated purelymail_user resource")
+	// Targets are compared in their normalized form, so respelling a phone
+	// number doesn't delete it.
+	planTargets := make(map[string]bool)
+	for _, method := range planMethods {
+		planTargets[passwordResetMethodTarget(method)] = true
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	currentByTarget := make(map[string]PasswordResetMethodModel)
+	for _, method := range currentMethods {
+		currentByTarget[passwordResetMethodTarget(method)] = method
+	}
+
+	// Get the methods that are deleted if they are no longer in the plan
+	var managedMethods []PasswordResetMethodModel
+	switch mode, stateMode := passwordResetMethodsMode(data), passwordResetMethodsMode(state); {
+	case mode == passwordResetMethodsAuthoritative:
+		managedMethods = currentMethods
+	case mode == stateMode:
+		managedMethods, diags = passwordResetMethodList(ctx, state.PasswordResetMethods)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	for _, method := range managedMethods {
+		current, ok := currentByTarget[passwordResetMethodTarget(method)]
+		if !ok || planTargets[passwordResetMethodTarget(method)] {
+			continue
+		}
+
+		// Delete this method by the target the API knows it by
+		setStep(ctx, fmt.Sprintf("delete password reset method %s", current.Target))
+		delResp, err := r.client.DeletePasswordResetMethod(ctx, api.DeletePasswordResetRequest{
+			UserName: data.UserName.ValueString(),
+			Target:   current.Target,
+		})
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to delete password reset method: %s", err))
+			return diags
+		}
+		defer delResp.Body.Close()
+
+		if delResp.StatusCode != http.StatusOK {
+			diags.AddError("API Error", fmt.Sprintf("Failed to delete password reset method (status: %d)", delResp.StatusCode))
+			return diags
+		}
+	}
+
+	// Upsert methods in the plan that are new or changed. Existing methods are
+	// updated in place by their existingTarget.
+	for _, method := range planMethods {
+		target := passwordResetMethodTarget(method)
+		upsertReq := api.UpsertPasswordResetRequest{
+			UserName: data.UserName.ValueString(),
+			Type:     api.UserPasswordResetMethodType(method.Type.ValueString()),
+			Target:   target,
+		}
+
+		if current, ok := currentByTarget[target]; ok {
+			if current.Type.Equal(method.Type) && current.Description.Equal(method.Description) &&
+				current.AllowMfaReset.Equal(method.AllowMfaReset) {
+				continue
+			}
+			upsertReq.ExistingTarget = &current.Target
+		}
+
+		if !method.Description.IsNull() && !method.Description.IsUnknown() {
+			desc := method.Description.ValueString()
+			upsertReq.Description = &desc
+		}
+
+		if !method.AllowMfaReset.IsNull() && !method.AllowMfaReset.IsUnknown() {
+			allow := method.AllowMfaReset.ValueBool()
+			upsertReq.AllowMfaReset = &allow
+		}
+
+		setStep(ctx, fmt.Sprintf("upsert password reset method %s", target))
+		upsertResp, err := r.client.CreateOrUpdatePasswordResetMethod(ctx, upsertReq)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to upsert password reset method: %s", err))
+			return diags
+		}
+		defer upsertResp.Body.Close()
+
+		if upsertResp.StatusCode != http.StatusOK {
+			diags.AddError("API Error", fmt.Sprintf("Failed to upsert password reset method (status: %d)", upsertResp.StatusCode))
+			return diags
+		}
+	}
+
+	return diags
 }
 
-func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data UserResourceModel
+// deleteUser deletes the user. Its password reset methods are deleted with it.
+func (r *UserResource) deleteUser(ctx context.Context, userName string) error {
+	setStep(ctx, "delete user")
+	httpResp, err := r.client.DeleteUser(ctx, api.DeleteUserJSONRequestBody{
+		UserName: userName,
+	})
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete user (status: %d)", httpResp.StatusCode)
+	}
+
+	return nil
+}
+
+// forwardDeletedUser creates a routing rule that forwards mail for a deleted
+// user to on_destroy_forward_to. The user is gone either way, so it is removed
+// from state even if the rule can't be created.
+func (r *UserResource) forwardDeletedUser(ctx context.Context, data *UserResourceModel, resp *resource.DeleteResponse) {
+	userName := normalizedOrRaw(normalizeMailboxName, data.UserName.ValueString())
+
+	var targetAddresses []string
+	resp.Diagnostics.Append(data.OnDestroyForwardTo.ElementsAs(ctx, &targetAddresses, false)...)
 	if resp.Diagnostics.HasError() {
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	// Note: Password reset methods are automatically deleted when the user is deleted
-	// No need to explicitly delete them
+	notForwarded := func(reason string) {
+		resp.Diagnostics.AddError(
+			"Mail Not Forwarded",
+			fmt.Sprintf("User %s was deleted, but mail to it is not forwarded to %s: %s. Create a purelymail_routing_rule for it instead.",
+				userName, strings.Join(targetAddresses, ", "), reason),
+		)
+		resp.State.RemoveResource(ctx)
+	}
 
-	httpResp, err := r.client.DeleteUser(ctx, api.DeleteUserJSONRequestBody{
-		UserName: data.UserName.ValueString(),
+	domainName, ok := emailDomain(userName)
+	if !ok {
+		notForwarded("user_name has no domain")
+		return
+	}
+	matchUser := strings.TrimSuffix(userName, "@"+domainName)
+
+	// Don't add a second rule for an address that is already routed
+	setStep(ctx, "check forwarding routing rules")
+	rules, err := listRoutingRules(ctx, r.client)
+	if err != nil {
+		notForwarded(err.Error())
+		return
+	}
+	if existing := findExactRoutingRule(rules, domainName, matchUser); existing != nil {
+		var existingTargets []string
+		if existing.TargetAddresses != nil {
+			existingTargets = *existing.TargetAddresses
+		}
+		resp.Diagnostics.AddWarning(
+			"Mail Not Forwarded",
+			fmt.Sprintf("User %s was deleted, but mail to it is already routed to %s by routing rule %d, so no rule forwarding it to %s was created.",
+				userName, strings.Join(existingTargets, ", "), *existing.Id, strings.Join(targetAddresses, ", ")),
+		)
+		return
+	}
+
+	setStep(ctx, "create forwarding routing rule")
+	httpResp, err := r.client.CreateRoutingRule(ctx, api.CreateRoutingRequest{
+		DomainName:      domainName,
+		Prefix:          false,
+		MatchUser:       matchUser,
+		TargetAddresses: targetAddresses,
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete user: %s", err))
+		notForwarded(fmt.Sprintf("unable to create routing rule: %s", err))
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete user (status: %d)", httpResp.StatusCode))
+		notForwarded(fmt.Sprintf("failed to create routing rule (status: %d)", httpResp.StatusCode))
 		return
 	}
 
-	tflog.Trace(ctx, "deleted purelymail_user resource")
+	var createResp struct {
+		Result *struct {
+			RoutingRuleId *int32 `json:"routingRuleId"`
+		} `json:"result"`
+	}
+	var id int32
+	if err := json.NewDecoder(httpResp.Body).Decode(&createResp); err == nil && createResp.Result != nil && createResp.Result.RoutingRuleId != nil {
+		id = *createResp.Result.RoutingRuleId
+	} else {
+		// There was no exact-match rule for the address before, so the one
+		// there now is the rule just created
+		setStep(ctx, "read forwarding routing rule")
+		rules, err := listRoutingRules(ctx, r.client)
+		if err == nil {
+			if rule := findExactRoutingRule(rules, domainName, matchUser); rule != nil {
+				id = *rule.Id
+			} else {
+				err = fmt.Errorf("routing rule not found")
+			}
+		}
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Mail Forwarded After Deletion",
+				fmt.Sprintf("User %s was deleted and mail to it is now forwarded to %s, but the ID of the routing rule could not be read: %s.",
+					userName, strings.Join(targetAddresses, ", "), err),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Mail Forwarded After Deletion",
+		fmt.Sprintf("User %s was deleted and mail to it is now forwarded to %s by routing rule %d. "+
+			"Import it as a purelymail_routing_rule with ID %d to manage it, or delete it when forwarding is no longer needed.",
+			userName, strings.Join(targetAddresses, ", "), id, id),
+	)
 }
 
-func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Use the username as the import identifier
-	resource.ImportStatePassthroughID(ctx, path.Root("user_name"), req, resp)
+// findExactRoutingRule returns the exact-match routing rule for the address
+// matchUser@domainName, or nil if there is none.
+func findExactRoutingRule(rules []api.RoutingRule, domainName string, matchUser string) *api.RoutingRule {
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Id != nil &&
+			rule.DomainName != nil && sameDomainName(*rule.DomainName, domainName) &&
+			rule.MatchUser != nil && *rule.MatchUser == matchUser &&
+			rule.Prefix != nil && !*rule.Prefix {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// setPasswordHash stores the hash of the password that was just sent, or null
+// if no password is configured.
+func setPasswordHash(data *UserResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.Password.IsNull() || data.Password.IsUnknown() {
+		data.PasswordHash = types.StringNull()
+		return diags
+	}
+
+	hash, err := hashPassword(data.Password.ValueString())
+	if err != nil {
+		diags.AddError("Password Hash Error", fmt.Sprintf("Unable to hash password: %s", err))
+		data.PasswordHash = types.StringNull()
+		return diags
+	}
+	data.PasswordHash = types.StringValue(hash)
+
+	return diags
+}
+
+// planPasswordHash returns the planned password_hash: the stored hash if it
+// matches the configured password, or unknown if the password has to be sent.
+func planPasswordHash(password types.String, stateHash types.String) types.String {
+	if password.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if password.IsNull() {
+		return types.StringNull()
+	}
+	if stateHash.IsNull() || stateHash.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	// Hashes that can't be verified are replaced
+	match, err := verifyPassword(password.ValueString(), stateHash.ValueString())
+	if err != nil || !match {
+		return types.StringUnknown()
+	}
+
+	return stateHash
 }
 
 // Helper functions.
@@ -562,10 +1577,29 @@ func valueBoolPtr(v types.Bool) *bool {
 	return &b
 }
 
+// setImportedUserDefaults sets the password_reset_methods_mode,
+// deletion_protection, adopt_existing and on_create_failure values that
+// imported users have none of yet.
+func setImportedUserDefaults(data *UserResourceModel) {
+	if data.PasswordResetMethodsMode.IsNull() {
+		data.PasswordResetMethodsMode = types.StringValue(passwordResetMethodsAuthoritative)
+	}
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+	if data.OnCreateFailure.IsNull() {
+		data.OnCreateFailure = types.StringValue(onCreateFailureRollback)
+	}
+}
+
 // readUser reads the user data from the API including password reset methods.
 // Returns (found bool, error).
 func (r *UserResource) readUser(ctx context.Context, data *UserResourceModel) (bool, error) {
 	// Read user from API
+	setStep(ctx, "read user")
 	httpResp, err := r.client.GetUser(ctx, api.GetUserJSONRequestBody{
 		UserName: data.UserName.ValueString(),
 	})
@@ -605,93 +1639,138 @@ func (r *UserResource) readUser(ctx context.Context, data *UserResourceModel) (b
 		data.RequireTwoFactorAuthentication = types.BoolValue(false)
 	}
 
-	// Read password reset methods
-	listResp, err := r.client.ListPasswordResetMethods(ctx, api.ListPasswordResetRequest{
-		UserName: data.UserName.ValueString(),
-	})
+	// Read password reset methods, unless they are ignored
+	mode := passwordResetMethodsMode(data)
+	if mode == passwordResetMethodsIgnore {
+		data.PasswordResetMethods = types.MapNull(types.ObjectType{AttrTypes: passwordResetMethodAttrTypes})
+		return true, nil
+	}
+
+	methods, err := listPasswordResetMethods(ctx, r.client, data.UserName.ValueString())
 	if err != nil {
-		return false, fmt.Errorf("unable to list password reset methods: %w", err)
-	}
-	defer listResp.Body.Close()
-
-	if listResp.StatusCode == http.StatusOK {
-		var listPasswordResetResp api.ListPasswordResetResponse
-		if err := json.NewDecoder(listResp.Body).Decode(&listPasswordResetResp); err == nil {
-			if listPasswordResetResp.Result != nil && listPasswordResetResp.Result.Users != nil {
-				// Convert API response to Terraform model
-				methodModels := []PasswordResetMethodModel{}
-				for _, method := range *listPasswordResetResp.Result.Users {
-					model := PasswordResetMethodModel{}
-					if method.Type != nil {
-						model.Type = types.StringValue(*method.Type)
-					}
-					if method.Target != nil {
-						model.Target = types.StringValue(*method.Target)
-					}
-					if method.Description != nil {
-						model.Description = types.StringValue(*method.Description)
-					} else {
-						model.Description = types.StringNull()
-					}
-					if method.AllowMfaReset != nil {
-						model.AllowMfaReset = types.BoolValue(*method.AllowMfaReset)
-					} else {
-						model.AllowMfaReset = types.BoolValue(false)
-					}
-					methodModels = append(methodModels, model)
-				}
+		return false, err
+	}
 
-				// Convert to types.List
-				elementType := types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"type":            types.StringType,
-						"target":          types.StringType,
-						"description":     types.StringType,
-						"allow_mfa_reset": types.BoolType,
-					},
-				}
+	// Keep the spelling of targets that are already known, the API returns
+	// them normalized. In additive mode, only known targets are read.
+	var knownTargets []string
+	if !data.PasswordResetMethods.IsNull() && !data.PasswordResetMethods.IsUnknown() {
+		knownTargets = slices.Collect(maps.Keys(data.PasswordResetMethods.Elements()))
+	}
 
-				if len(methodModels) > 0 {
-					elements := []attr.Value{}
-					for _, method := range methodModels {
-						obj, diags := types.ObjectValue(
-							elementType.AttrTypes,
-							map[string]attr.Value{
-								"type":            method.Type,
-								"target":          method.Target,
-								"description":     method.Description,
-								"allow_mfa_reset": method.AllowMfaReset,
-							},
-						)
-						if diags.HasError() {
-							return false, fmt.Errorf("unable to create object value for password reset method")
-						}
-						elements = append(elements, obj)
-					}
-
-					listValue, diags := types.ListValue(elementType, elements)
-					if diags.HasError() {
-						return false, fmt.Errorf("unable to create list value for password reset methods")
-					}
-					data.PasswordResetMethods = listValue
-				} else {
-					// Empty list
-					data.PasswordResetMethods = types.ListNull(elementType)
-				}
-			} else {
-				// No password reset methods
-				elementType := types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"type":            types.StringType,
-						"target":          types.StringType,
-						"description":     types.StringType,
-						"allow_mfa_reset": types.BoolType,
-					},
-				}
-				data.PasswordResetMethods = types.ListNull(elementType)
-			}
+	elementType := types.ObjectType{AttrTypes: passwordResetMethodAttrTypes}
+	elements := make(map[string]attr.Value, len(methods))
+	for _, method := range methods {
+		known := slices.IndexFunc(knownTargets, func(target string) bool {
+			return samePasswordResetTarget(target, method.Target)
+		})
+		if known >= 0 {
+			method.Target = knownTargets[known]
+		} else if mode == passwordResetMethodsAdditive {
+			// Managed elsewhere
+			continue
+		}
+
+		obj, diags := types.ObjectValue(
+			elementType.AttrTypes,
+			map[string]attr.Value{
+				"type":            method.Type,
+				"description":     method.Description,
+				"allow_mfa_reset": method.AllowMfaReset,
+			},
+		)
+		if diags.HasError() {
+			return false, fmt.Errorf("unable to create object value for password reset method")
 		}
+		elements[method.Target] = obj
+	}
+
+	if len(elements) == 0 {
+		data.PasswordResetMethods = types.MapNull(elementType)
+		return true, nil
 	}
 
+	mapValue, diags := types.MapValue(elementType, elements)
+	if diags.HasError() {
+		return false, fmt.Errorf("unable to create map value for password reset methods")
+	}
+	data.PasswordResetMethods = mapValue
+
 	return true, nil
 }
+
+// listPasswordResetMethods returns the password reset methods of a user with
+// their targets as the API returns them.
+func listPasswordResetMethods(ctx context.Context, client *api.Client, userName string) ([]PasswordResetMethodModel, error) {
+	setStep(ctx, "list password reset methods")
+	httpResp, err := client.ListPasswordResetMethods(ctx, api.ListPasswordResetRequest{
+		UserName: userName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list password reset methods: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list password reset methods (status: %d)", httpResp.StatusCode)
+	}
+
+	var listResp api.ListPasswordResetResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("unable to decode password reset methods response: %w", err)
+	}
+
+	if listResp.Result == nil || listResp.Result.Users == nil {
+		return nil, nil
+	}
+
+	methods := make([]PasswordResetMethodModel, 0, len(*listResp.Result.Users))
+	for _, method := range *listResp.Result.Users {
+		if method.Target == nil {
+			continue
+		}
+
+		model := PasswordResetMethodModel{
+			Target:        *method.Target,
+			Type:          types.StringNull(),
+			Description:   types.StringNull(),
+			AllowMfaReset: types.BoolValue(false),
+		}
+		if method.Type != nil {
+			model.Type = types.StringValue(string(*method.Type))
+		}
+		if method.Description != nil {
+			model.Description = types.StringValue(*method.Description)
+		}
+		if method.AllowMfaReset != nil {
+			model.AllowMfaReset = types.BoolValue(*method.AllowMfaReset)
+		}
+		methods = append(methods, model)
+	}
+
+	return methods, nil
+}
+
+// listUsers returns the names of all users of the account.
+func listUsers(ctx context.Context, client *api.Client) ([]string, error) {
+	httpResp, err := client.ListUsers(ctx, api.EmptyRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list users: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list users (status: %d)", httpResp.StatusCode)
+	}
+
+	var listResp api.ListUserResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	if listResp.Result == nil || listResp.Result.Users == nil {
+		return nil, nil
+	}
+
+	return *listResp.Result.Users, nil
+}
@@ -1,13 +1,27 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
 	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
 )
@@ -80,23 +94,53 @@ func TestAccUserResourcePasswordWo(t *testing.T) {
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
 
+	expectPassword := func(password string, changes int) func() {
+		return func() {
+			gotPassword, gotChanges := mockServer.UserPassword("bob")
+			if gotPassword != password || gotChanges != changes {
+				t.Errorf("expected password %q set %d times, got %q set %d times", password, changes, gotPassword, gotChanges)
+			}
+		}
+	}
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Create with password_wo
 			{
-				Config: testAccUserResourceConfigPasswordWo(ts.URL),
+				Config: testAccUserResourceConfigPasswordWo(ts.URL, "initial-password-123", 1, true),
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("user_name"), knownvalue.StringExact("bob")),
 					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("id"), knownvalue.StringExact("bob")),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_wo"), knownvalue.Null()),
 				},
 			},
-			// Update with password_wo
+			// Unrelated updates don't send password_wo
 			{
-				Config: testAccUserResourceConfigPasswordWoUpdated(ts.URL),
+				PreConfig: expectPassword("initial-password-123", 1),
+				Config:    testAccUserResourceConfigPasswordWo(ts.URL, "updated-password-456", 1, false),
 				ConfigStateChecks: []statecheck.StateCheck{
-					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("user_name"), knownvalue.StringExact("bob")),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("enable_search_indexing"), knownvalue.Bool(false)),
+				},
+			},
+			// Bumping password_wo_version sends it
+			{
+				PreConfig: expectPassword("initial-password-123", 1),
+				Config:    testAccUserResourceConfigPasswordWo(ts.URL, "updated-password-456", 2, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_user.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				PreConfig: expectPassword("updated-password-456", 2),
+				Config:    testAccUserResourceConfigPasswordWo(ts.URL, "updated-password-456", 2, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
 				},
 			},
 			// Delete testing automatically occurs
@@ -104,30 +148,1212 @@ func TestAccUserResourcePasswordWo(t *testing.T) {
 	})
 }
 
-func testAccUserResourceConfigPasswordWo(endpoint string) string {
-	return `
+func testAccUserResourceConfigPasswordWo(endpoint string, password string, version int, enableSearchIndexing bool) string {
+	return fmt.Sprintf(`
 provider "purelymail" {
-  endpoint  = "` + endpoint + `"
+  endpoint  = %[1]q
   api_token = "test-token"
 }
 
 resource "purelymail_user" "test" {
-  user_name   = "bob"
+  user_name              = "bob"
+  password_wo            = %[2]q
+  password_wo_version    = %[3]d
+  enable_search_indexing = %[4]t
+}
+`, endpoint, password, version, enableSearchIndexing)
+}
+
+func TestAccUserResourcePasswordHash(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	expectPassword := func(password string, changes int) func() {
+		return func() {
+			gotPassword, gotChanges := mockServer.UserPassword("carol")
+			if gotPassword != password || gotChanges != changes {
+				t.Errorf("expected password %q set %d times, got %q set %d times", password, changes, gotPassword, gotChanges)
+			}
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create stores only the hash
+			{
+				Config: testAccUserResourceConfigPasswordHash(ts.URL, "initial-password-123", true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password"), knownvalue.Null()),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_hash"), knownvalue.StringRegexp(regexp.MustCompile(`^\$argon2id\$`))),
+				},
+			},
+			// The same password matches the hash
+			{
+				PreConfig: expectPassword("initial-password-123", 1),
+				Config:    testAccUserResourceConfigPasswordHash(ts.URL, "initial-password-123", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Unrelated updates don't send the password
+			{
+				Config: testAccUserResourceConfigPasswordHash(ts.URL, "initial-password-123", false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_hash"), knownvalue.StringRegexp(regexp.MustCompile(`^\$argon2id\$`))),
+					},
+				},
+			},
+			// Changing the password is planned and sent
+			{
+				PreConfig: expectPassword("initial-password-123", 1),
+				Config:    testAccUserResourceConfigPasswordHash(ts.URL, "updated-password-456", false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_user.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("purelymail_user.test", tfjsonpath.New("password_hash")),
+					},
+				},
+			},
+			// Removing the password clears the hash
+			{
+				PreConfig: expectPassword("updated-password-456", 2),
+				Config:    testAccUserResourceConfigPasswordHash(ts.URL, "", false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_hash"), knownvalue.Null()),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccUserResourceConfigPasswordHash(endpoint string, password string, enableSearchIndexing bool) string {
+	passwordAttr := ""
+	if password != "" {
+		passwordAttr = fmt.Sprintf("password               = %q", password)
+	}
+
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name              = "carol"
+  %[2]s
+  enable_search_indexing = %[3]t
+}
+`, endpoint, passwordAttr, enableSearchIndexing)
+}
+
+func TestUserResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &UserResource{}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	req := fwresource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"user_name":"alice","new_user_name":null,"password":"plaintext-password","password_wo":null,"enable_search_indexing":true,"require_two_factor_authentication":false,"password_reset_methods":null,"deletion_protection":false,"adopt_existing":false,"on_create_failure":"rollback","id":"alice"}`),
+		},
+	}
+	var resp fwresource.UpgradeStateResponse
+	r.UpgradeState(ctx)[0].StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	upgraded, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %s", err)
+	}
+
+	var attrs map[string]tftypes.Value
+	if err := upgraded.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	if !attrs["password"].IsNull() {
+		t.Errorf("expected password to be removed, got %s", attrs["password"])
+	}
+	var hash string
+	if err := attrs["password_hash"].As(&hash); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := verifyPassword("plaintext-password", hash); err != nil || !ok {
+		t.Errorf("expected password_hash to match the prior password, got %q (%v)", hash, err)
+	}
+}
+
+func TestUserResourceUpgradeStateV1(t *testing.T) {
+	ctx := context.Background()
+	r := &UserResource{}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	req := fwresource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"user_name":"alice","new_user_name":null,"password":null,"password_hash":null,"password_wo":null,"password_wo_version":null,"enable_search_indexing":true,"require_two_factor_authentication":true,"password_reset_methods":[{"type":"email","target":"alice@recovery.example.com","description":"Backup email","allow_mfa_reset":true},{"type":"phone","target":"+15555550100","description":null,"allow_mfa_reset":false}],"deletion_protection":false,"adopt_existing":false,"on_create_failure":"rollback","id":"alice"}`),
+		},
+	}
+	var resp fwresource.UpgradeStateResponse
+	r.UpgradeState(ctx)[1].StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	upgraded, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %s", err)
+	}
+
+	var attrs map[string]tftypes.Value
+	if err := upgraded.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	var methods map[string]tftypes.Value
+	if err := attrs["password_reset_methods"].As(&methods); err != nil {
+		t.Fatal(err)
+	}
+	for target, methodType := range map[string]string{"alice@recovery.example.com": "email", "+15555550100": "phone"} {
+		var method map[string]tftypes.Value
+		if err := methods[target].As(&method); err != nil {
+			t.Fatalf("expected a password reset method for %s: %s", target, err)
+		}
+		var got string
+		if err := method["type"].As(&got); err != nil || got != methodType {
+			t.Errorf("expected password reset method %s to have type %q, got %q (%v)", target, methodType, got, err)
+		}
+	}
+	if len(methods) != 2 {
+		t.Errorf("expected 2 password reset methods, got %d", len(methods))
+	}
+}
+
+func TestAccUserResourceDeletionProtection(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a protected user
+			{
+				Config: testAccUserResourceConfigDeletionProtection(ts.URL, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("deletion_protection"), knownvalue.Bool(true)),
+				},
+			},
+			// Destroying a protected user fails
+			{
+				Config:      testAccUserResourceConfigDeletionProtection(ts.URL, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Deletion Protection Enabled"),
+			},
+			// Lift the protection
+			{
+				Config: testAccUserResourceConfigDeletionProtection(ts.URL, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("deletion_protection"), knownvalue.Bool(false)),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccUserResourceConfigDeletionProtection(endpoint string, deletionProtection bool) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name           = "dave@example.com"
+  deletion_protection = %[2]t
+}
+`, endpoint, deletionProtection)
+}
+
+func TestAccUserResourceAdoptExisting(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Seed a user that was created outside of Terraform
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp, err := client.CreateUser(context.Background(), api.CreateUserJSONRequestBody{UserName: "erin@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+	httpResp, err = client.CreateOrUpdatePasswordResetMethod(context.Background(), api.UpsertPasswordResetRequest{
+		UserName: "erin@example.com",
+		Type:     "email",
+		Target:   "old@recovery.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Creating a user that already exists fails with the API's error
+			{
+				Config:      testAccUserResourceConfigAdoptExisting(ts.URL, false),
+				ExpectError: regexp.MustCompile(`Failed to create user \(status: 400\):\s+user already exists`),
+			},
+			// Adopting reconciles the existing user to the configuration
+			{
+				Config: testAccUserResourceConfigAdoptExisting(ts.URL, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("id"), knownvalue.StringExact("erin@example.com")),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("enable_search_indexing"), knownvalue.Bool(false)),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.MapExact(map[string]knownvalue.Check{
+						"new@recovery.example.com": knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"type": knownvalue.StringExact("email"),
+						}),
+					})),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccUserResourceConfigAdoptExisting(endpoint string, adoptExisting bool) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name              = "erin@example.com"
+  enable_search_indexing = false
+  adopt_existing         = %[2]t
+
+  password_reset_methods = {
+    "new@recovery.example.com" = {
+      type = "email"
+    }
+  }
+}
+`, endpoint, adoptExisting)
+}
+
+func TestAccUserResourceCreateFailure(t *testing.T) {
+	// Each step of creating a user after the user itself, as an injected fault
+	failurePoints := []struct {
+		name            string
+		operation       string
+		skip            int
+		basicCreateUser bool
+	}{
+		// Settings only need ModifyUser if createUser doesn't accept them
+		{name: "settings", operation: "ModifyUser", skip: 0, basicCreateUser: true},
+		{name: "password_reset_method", operation: "CreateOrUpdatePasswordResetMethod", skip: 0},
+		{name: "two_factor", operation: "ModifyUser", skip: 0},
+	}
+
+	testCases := []struct {
+		name            string
+		onCreateFailure string
+		failRollback    bool
+		expectError     string
+		expectUser      bool
+	}{
+		{name: "rollback", onCreateFailure: "rollback", expectError: "User Creation Rolled Back"},
+		{name: "rollback_failed", onCreateFailure: "rollback", failRollback: true, expectError: "Rollback Failed", expectUser: true},
+		{name: "taint", onCreateFailure: "taint", expectError: "User Partially Created", expectUser: true},
+	}
+
+	for _, tc := range testCases {
+		for _, fp := range failurePoints {
+			t.Run(tc.name+"/"+fp.name, func(t *testing.T) {
+				// Create mock server using generated ServerInterface
+				mockServer := mock.NewServer()
+				handler := api.Handler(mockServer)
+				ts := httptest.NewServer(handler)
+				defer ts.Close()
+
+				client, err := api.NewClient(ts.URL)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// After a rollback nothing is in state, so the next apply creates the user.
+				// Otherwise the tainted user is replaced.
+				expectAction := plancheck.ResourceActionCreate
+				if tc.expectUser {
+					expectAction = plancheck.ResourceActionReplace
+				}
+
+				resource.Test(t, resource.TestCase{
+					PreCheck:                 func() { testAccPreCheck(t) },
+					ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+					Steps: []resource.TestStep{
+						{
+							PreConfig: func() {
+								mockServer.SetBasicCreateUser(fp.basicCreateUser)
+								mockServer.InjectFault(fp.operation, fp.skip)
+								if tc.failRollback {
+									mockServer.InjectFault("DeleteUser", 0)
+								}
+							},
+							Config:      testAccUserResourceConfigCreateFailure(ts.URL, tc.onCreateFailure),
+							ExpectError: regexp.MustCompile(tc.expectError),
+						},
+						{
+							PreConfig: func() {
+								httpResp, err := client.GetUser(context.Background(), api.GetUserJSONRequestBody{UserName: "frank@example.com"})
+								if err != nil {
+									t.Fatal(err)
+								}
+								httpResp.Body.Close()
+								if exists := httpResp.StatusCode == http.StatusOK; exists != tc.expectUser {
+									t.Fatalf("expected user to exist: %t, got status %d", tc.expectUser, httpResp.StatusCode)
+								}
+							},
+							Config: testAccUserResourceConfigCreateFailure(ts.URL, tc.onCreateFailure),
+							ConfigPlanChecks: resource.ConfigPlanChecks{
+								PreApply: []plancheck.PlanCheck{
+									plancheck.ExpectResourceAction("purelymail_user.test", expectAction),
+								},
+							},
+							ConfigStateChecks: []statecheck.StateCheck{
+								statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("require_two_factor_authentication"), knownvalue.Bool(true)),
+							},
+						},
+						// Delete testing automatically occurs
+					},
+				})
+			})
+		}
+	}
+}
+
+func testAccUserResourceConfigCreateFailure(endpoint string, onCreateFailure string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name                         = "frank@example.com"
+  password                          = "initial-password-123"
+  require_two_factor_authentication = true
+  on_create_failure                 = %[2]q
+
+  # Methods that allow MFA resets can't be sent with createUser
+  password_reset_methods = {
+    "frank@recovery.example.com" = {
+      type            = "email"
+      allow_mfa_reset = true
+    }
+  }
+}
+`, endpoint, onCreateFailure)
+}
+
+func TestRejectsCreateUserFields(t *testing.T) {
+	cases := map[string]bool{
+		"unknown field in createUser request":        true,
+		`Unrecognized field "recoveryEmail"`:         true,
+		"enableSearchIndexing is not supported":      true,
+		"user already exists":                        false,
+		"password must be at least 8 characters":     false,
+		"userName is not on a domain of the account": false,
+		"": false,
+	}
+
+	for message, want := range cases {
+		if got := rejectsCreateUserFields(message); got != want {
+			t.Errorf("rejectsCreateUserFields(%q) = %t, want %t", message, got, want)
+		}
+	}
+}
+
+func TestCreateUserRequestEnablesPasswordReset(t *testing.T) {
+	data := UserResourceModel{UserName: NewMailboxNameValue("alice@example.com")}
+
+	createReq, _ := createUserRequest(&data, nil)
+	if createReq.EnablePasswordReset != nil {
+		t.Errorf("expected enablePasswordReset to be unset without recovery targets")
+	}
+
+	createReq, included := createUserRequest(&data, []PasswordResetMethodModel{
+		{Target: "alice@recovery.example.com", Type: types.StringValue("email"), AllowMfaReset: types.BoolValue(false)},
+	})
+	if !included[0] || createReq.RecoveryEmail == nil {
+		t.Fatalf("expected the email method to be sent as recoveryEmail")
+	}
+	if createReq.EnablePasswordReset == nil || !*createReq.EnablePasswordReset {
+		t.Errorf("expected enablePasswordReset = true with a recovery target")
+	}
+}
+
+func TestAccUserResourceTimeouts(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Applying the settings hangs after the user was created, the
+			// rollback still runs after the timeout
+			{
+				PreConfig: func() {
+					mockServer.SetBasicCreateUser(true)
+					mockServer.SetDelay("ModifyUser", time.Minute)
+				},
+				Config:      testAccUserResourceConfigTimeouts(ts.URL, "1s"),
+				ExpectError: regexp.MustCompile(`(?s)User\s+Creation\s+Rolled\s+Back.*timed\s+out\s+during\s+step\s+"set\s+password\s+and\s+search\s+indexing"`),
+			},
+			{
+				PreConfig: func() {
+					httpResp, err := client.GetUser(context.Background(), api.GetUserJSONRequestBody{UserName: "grace@example.com"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					httpResp.Body.Close()
+					if httpResp.StatusCode == http.StatusOK {
+						t.Fatal("expected the user to be rolled back")
+					}
+
+					mockServer.SetDelay("ModifyUser", 0)
+				},
+				Config: testAccUserResourceConfigTimeouts(ts.URL, "1m"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("timeouts").AtMapKey("create"), knownvalue.StringExact("1m")),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("enable_search_indexing"), knownvalue.Bool(false)),
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccUserResourceConfigTimeouts(endpoint string, createTimeout string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name              = "grace@example.com"
+  password               = "initial-password-123"
+  enable_search_indexing = false
+
+  timeouts {
+    create = %[2]q
+  }
+}
+`, endpoint, createTimeout)
+}
+
+func TestAccUserResourceSingleCallCreate(t *testing.T) {
+	for _, basicCreateUser := range []bool{false, true} {
+		t.Run(fmt.Sprintf("basic_create_user=%t", basicCreateUser), func(t *testing.T) {
+			// Create mock server using generated ServerInterface
+			mockServer := mock.NewServer()
+			handler := api.Handler(mockServer)
+			ts := httptest.NewServer(handler)
+			defer ts.Close()
+
+			if basicCreateUser {
+				// Falls back to applying the settings with separate calls
+				mockServer.SetBasicCreateUser(true)
+			} else {
+				// Everything is sent with createUser, so any other call fails the create
+				mockServer.InjectFault("ModifyUser", 0)
+				mockServer.InjectFault("CreateOrUpdatePasswordResetMethod", 0)
+			}
+
+			resource.Test(t, resource.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: testAccUserResourceConfigSingleCallCreate(ts.URL),
+						ConfigStateChecks: []statecheck.StateCheck{
+							statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("enable_search_indexing"), knownvalue.Bool(false)),
+							statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.MapExact(map[string]knownvalue.Check{
+								"grace@recovery.example.com": knownvalue.ObjectExact(map[string]knownvalue.Check{
+									"type":            knownvalue.StringExact("email"),
+									"description":     knownvalue.StringExact("Backup email"),
+									"allow_mfa_reset": knownvalue.Bool(false),
+								}),
+								"+15555550100": knownvalue.ObjectExact(map[string]knownvalue.Check{
+									"type":            knownvalue.StringExact("phone"),
+									"description":     knownvalue.Null(),
+									"allow_mfa_reset": knownvalue.Bool(false),
+								}),
+							})),
+						},
+					},
+					// Delete testing automatically occurs
+				},
+			})
+		})
+	}
+}
+
+func testAccUserResourceConfigSingleCallCreate(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name              = "grace@example.com"
+  password               = "initial-password-123"
+  enable_search_indexing = false
+
+  password_reset_methods = {
+    "grace@recovery.example.com" = {
+      type        = "email"
+      description = "Backup email"
+    }
+    "+15555550100" = {
+      type = "phone"
+    }
+  }
+}
+`, endpoint)
+}
+
+func TestAccUserResourceValidation(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name   = "dave@example.com"
+  password    = "initial-password-123"
   password_wo = "initial-password-123"
+`),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name                         = "dave@example.com"
+  require_two_factor_authentication = true
+`),
+				ExpectError: regexp.MustCompile(`Missing Password Reset Method`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name     = "dave@example.com"
+  new_user_name = "dave@example.com"
+`),
+				ExpectError: regexp.MustCompile(`new_user_name must differ from user_name`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name = "dave@example.com"
+  password_reset_methods = {
+    "555-0100" = { type = "phone" }
+  }
+`),
+				ExpectError: regexp.MustCompile(`password_reset_methods\["555-0100"\] must be a valid phone target`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name = "dave@example.com"
+  password_reset_methods = {
+    "+15555550100"      = { type = "phone" }
+    "+1 (555) 555-0100" = { type = "phone" }
+  }
+`),
+				ExpectError: regexp.MustCompile(`Duplicate Password Reset Method`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name                   = "dave@example.com"
+  password_reset_methods_mode = "ignore"
+  password_reset_methods = {
+    "dave@recovery.example.com" = { type = "email" }
+  }
+`),
+				ExpectError: regexp.MustCompile(`password_reset_methods can't be set when\s+password_reset_methods_mode\s+is\s+"ignore"`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name             = "dave@example.com"
+  on_destroy_forward_to = ["manager@example.com", "dave@EXAMPLE.com"]
+`),
+				ExpectError: regexp.MustCompile(`on_destroy_forward_to can't contain user_name`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name             = "dave"
+  on_destroy_forward_to = ["manager@example.com"]
+`),
+				ExpectError: regexp.MustCompile(`on_destroy_forward_to requires user_name to be a full\s+email\s+address`),
+			},
+			// Reset methods managed with standalone resources
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name                         = "dave@example.com"
+  require_two_factor_authentication = true
+  password_reset_methods_mode       = "additive"
+`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name = "dave@example.com"
+`),
+			},
+			{
+				Config: testAccUserResourceConfigValidation(ts.URL, `
+  user_name     = "dave@example.com"
+  new_user_name = "dave@exampel.com"
+`),
+				ExpectError: regexp.MustCompile(`Did you\s+mean example.com\?`),
+			},
+			// Delete testing automatically occurs
+		},
+	})
 }
-`
+
+func testAccUserResourceConfigValidation(endpoint string, attributes string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
 }
 
-func testAccUserResourceConfigPasswordWoUpdated(endpoint string) string {
-	return `
+resource "purelymail_domain" "test" {
+  name = "example.com"
+}
+
+resource "purelymail_user" "test" {
+%[2]s
+  depends_on = [purelymail_domain.test]
+}
+`, endpoint, attributes)
+}
+
+func TestAccUserResourceUserNameSpelling(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserResourceConfigUserName(ts.URL, "alice@"),
+				ExpectError: regexp.MustCompile(`Invalid Email Address`),
+			},
+			{
+				Config: testAccUserResourceConfigUserName(ts.URL, "Alice@Example.COM"),
+			},
+			// Purelymail user names ignore case, so changing only the case
+			// updates in place
+			{
+				Config: testAccUserResourceConfigUserName(ts.URL, "alice@example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_user.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("user_name"), knownvalue.StringExact("alice@example.com")),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("id"), knownvalue.StringExact("Alice@Example.COM")),
+				},
+			},
+			// Changing the user name replaces the user
+			{
+				Config: testAccUserResourceConfigUserName(ts.URL, "bob@example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("purelymail_user.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func TestAccUserResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceConfigUserName(ts.URL, "erin@Example.COM"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_user.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("erin@example.com"),
+					}),
+				},
+			},
+			// Changing only the spelling keeps the identity
+			{
+				Config: testAccUserResourceConfigUserName(ts.URL, "erin@example.com"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_user.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("erin@example.com"),
+					}),
+				},
+			},
+			{
+				ResourceName:    "purelymail_user.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccUserResourceConfigUserName(endpoint string, userName string) string {
+	return fmt.Sprintf(`
 provider "purelymail" {
-  endpoint  = "` + endpoint + `"
+  endpoint  = %[1]q
   api_token = "test-token"
 }
 
 resource "purelymail_user" "test" {
-  user_name   = "bob"
-  password_wo = "updated-password-456"
+  user_name = %[2]q
+}
+`, endpoint, userName)
+}
+
+func TestAccUserResourcePasswordResetMethodsMode(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Inline and standalone methods coexist in additive mode
+			{
+				Config: testAccUserResourceConfigPasswordResetMethodsMode(ts.URL, "additive", `
+    "heidi@recovery.example.com" = { type = "email" }
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.MapExact(map[string]knownvalue.Check{
+						"heidi@recovery.example.com": knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"type": knownvalue.StringExact("email"),
+						}),
+					})),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "heidi@example.com", "heidi@recovery.example.com", "+15555550100"),
+			},
+			// Removing an inline method leaves the standalone one alone
+			{
+				Config: testAccUserResourceConfigPasswordResetMethodsMode(ts.URL, "additive", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.Null()),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "heidi@example.com", "+15555550100"),
+			},
+			// Switching to ignore doesn't change any methods
+			{
+				Config: testAccUserResourceConfigPasswordResetMethodsMode(ts.URL, "ignore", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.Null()),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "heidi@example.com", "+15555550100"),
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func TestAccUserResourceExistingPasswordResetMethods(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Methods that already exist as configured must not be written again, so
+	// any write fails the apply
+	failWrites := func() {
+		mockServer.InjectFault("CreateOrUpdatePasswordResetMethod", 0)
+		mockServer.InjectFault("DeletePasswordResetMethod", 0)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// removed blocks
+			tfversion.SkipBelow(tfversion.Version1_7_0),
+		},
+		Steps: []resource.TestStep{
+			// Standalone method
+			{
+				Config: testAccUserResourceConfigExistingPasswordResetMethods(ts.URL, "standalone"),
+				Check:  testAccCheckPasswordResetTargets(ts.URL, "ivan@example.com", "ivan@recovery.example.com"),
+			},
+			// Take it over into the user with a removed block
+			{
+				PreConfig: failWrites,
+				Config:    testAccUserResourceConfigExistingPasswordResetMethods(ts.URL, "inline"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.MapExact(map[string]knownvalue.Check{
+						"ivan@recovery.example.com": knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"type":            knownvalue.StringExact("email"),
+							"description":     knownvalue.StringExact("Backup email"),
+							"allow_mfa_reset": knownvalue.Bool(true),
+						}),
+					})),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "ivan@example.com", "ivan@recovery.example.com"),
+			},
+			// Hand it back to a standalone resource with an import block
+			{
+				PreConfig: func() {
+					mockServer.ClearFaults()
+					failWrites()
+				},
+				Config: testAccUserResourceConfigExistingPasswordResetMethods(ts.URL, "import"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods"), knownvalue.Null()),
+					statecheck.ExpectKnownValue("purelymail_password_reset_method.email", tfjsonpath.New("description"), knownvalue.StringExact("Backup email")),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "ivan@example.com", "ivan@recovery.example.com"),
+			},
+			{
+				PreConfig: mockServer.ClearFaults,
+				Config:    testAccUserResourceConfigExistingPasswordResetMethods(ts.URL, "import"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+// testAccUserResourceConfigExistingPasswordResetMethods has the method as a
+// standalone resource, inline after removing that resource from state, or
+// standalone again by importing it.
+func testAccUserResourceConfigExistingPasswordResetMethods(endpoint string, stage string) string {
+	user := `
+resource "purelymail_user" "test" {
+  user_name                   = "ivan@example.com"
+  password_reset_methods_mode = "additive"
+}
+`
+	method := `
+resource "purelymail_password_reset_method" "email" {
+  user_name       = purelymail_user.test.user_name
+  type            = "email"
+  target          = "ivan@recovery.example.com"
+  description     = "Backup email"
+  allow_mfa_reset = true
+}
+`
+
+	switch stage {
+	case "inline":
+		user = `
+resource "purelymail_user" "test" {
+  user_name                   = "ivan@example.com"
+  password_reset_methods_mode = "additive"
+  password_reset_methods = {
+    "ivan@recovery.example.com" = {
+      type            = "email"
+      description     = "Backup email"
+      allow_mfa_reset = true
+    }
+  }
+}
+`
+		method = `
+removed {
+  from = purelymail_password_reset_method.email
+
+  lifecycle {
+    destroy = false
+  }
 }
 `
+	case "import":
+		user = `
+resource "purelymail_user" "test" {
+  user_name                   = "ivan@example.com"
+  password_reset_methods_mode = "ignore"
+}
+`
+		method += `
+import {
+  to = purelymail_password_reset_method.email
+  id = "ivan@example.com:ivan@recovery.example.com"
+}
+`
+	}
+
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+`, endpoint) + user + method
+}
+
+func testAccUserResourceConfigPasswordResetMethodsMode(endpoint string, mode string, methods string) string {
+	methodsAttr := ""
+	if methods != "" {
+		methodsAttr = "password_reset_methods = {" + methods + "  }"
+	}
+
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name                   = "heidi@example.com"
+  password_reset_methods_mode = %[2]q
+  %[3]s
+}
+
+resource "purelymail_password_reset_method" "phone" {
+  user_name = purelymail_user.test.user_name
+  type      = "phone"
+  target    = "+15555550100"
+}
+`, endpoint, mode, methodsAttr)
+}
+
+func TestAccUserResourceOnDestroyForwardTo(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceConfigOnDestroyForwardTo(ts.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("on_destroy_forward_to"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("manager@example.com"),
+					})),
+				},
+			},
+			// The user is deleted even if its mail can't be forwarded
+			{
+				PreConfig:   func() { mockServer.InjectFault("CreateRoutingRule", 0) },
+				Config:      testAccUserResourceConfigOnDestroyForwardTo(ts.URL),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Mail Not Forwarded`),
+			},
+			// Create the user again, it is forwarded when it is destroyed at the end
+			{
+				PreConfig: func() {
+					mockServer.ClearFaults()
+					if rules, err := listRoutingRules(context.Background(), client); err != nil || len(rules) != 0 {
+						t.Errorf("expected no routing rules after the failed forwarding, got %d (%v)", len(rules), err)
+					}
+				},
+				Config: testAccUserResourceConfigOnDestroyForwardTo(ts.URL),
+			},
+			// Delete testing automatically occurs
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			rules, err := listRoutingRules(context.Background(), client)
+			if err != nil {
+				return err
+			}
+			if len(rules) != 1 {
+				return fmt.Errorf("expected one forwarding routing rule, got %d", len(rules))
+			}
+
+			rule := rules[0]
+			if *rule.DomainName != "example.com" || *rule.MatchUser != "ivan" || *rule.Prefix ||
+				len(*rule.TargetAddresses) != 1 || (*rule.TargetAddresses)[0] != "manager@example.com" {
+				return fmt.Errorf("unexpected forwarding routing rule %+v", rule)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccUserResourceOnDestroyForwardToExistingRule(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceConfigOnDestroyForwardTo(ts.URL),
+				Check: func(s *terraform.State) error {
+					// Route the address before the user is destroyed
+					httpResp, err := client.CreateRoutingRule(context.Background(), api.CreateRoutingRequest{
+						DomainName:      "example.com",
+						MatchUser:       "ivan",
+						TargetAddresses: []string{"helpdesk@example.com"},
+					})
+					if err != nil {
+						return err
+					}
+					return httpResp.Body.Close()
+				},
+			},
+			// Delete testing automatically occurs
+		},
+		// The existing rule is kept and no second one is added
+		CheckDestroy: func(s *terraform.State) error {
+			rules, err := listRoutingRules(context.Background(), client)
+			if err != nil {
+				return err
+			}
+			if len(rules) != 1 || len(*rules[0].TargetAddresses) != 1 || (*rules[0].TargetAddresses)[0] != "helpdesk@example.com" {
+				return fmt.Errorf("expected only the existing routing rule, got %d", len(rules))
+			}
+			return nil
+		},
+	})
+}
+
+func TestUserResourceModifyPlanForwardOnReplace(t *testing.T) {
+	ctx := context.Background()
+	r := &UserResource{}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	value := func(json string) tftypes.Value {
+		v, err := (&tfprotov6.DynamicValue{JSON: []byte(json)}).Unmarshal(schemaType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	forwarded := `{"user_name":"ivan@example.com","on_destroy_forward_to":["manager@example.com"],"id":"ivan@example.com"}`
+	plain := `{"user_name":"ivan@example.com","id":"ivan@example.com"}`
+
+	cases := []struct {
+		name  string
+		state string
+		plan  string
+		warn  bool
+	}{
+		{name: "replace forwarded", state: forwarded, plan: strings.Replace(forwarded, `"user_name":"ivan@`, `"user_name":"ivana@`, 1), warn: true},
+		{name: "respell forwarded", state: forwarded, plan: strings.Replace(forwarded, `"user_name":"ivan@example.com"`, `"user_name":"ivan@Example.com"`, 1), warn: false},
+		{name: "replace plain", state: plain, plan: strings.Replace(plain, `"user_name":"ivan@`, `"user_name":"ivana@`, 1), warn: false},
+	}
+
+	for _, c := range cases {
+		req := fwresource.ModifyPlanRequest{
+			State:  tfsdk.State{Schema: schemaResp.Schema, Raw: value(c.state)},
+			Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: value(c.plan)},
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: value(c.plan)},
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", c.name, resp.Diagnostics)
+		}
+
+		if got := resp.Diagnostics.WarningsCount() > 0; got != c.warn {
+			t.Errorf("%s: got warning %t, want %t", c.name, got, c.warn)
+		}
+	}
+}
+
+func testAccUserResourceConfigOnDestroyForwardTo(endpoint string) string {
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name             = "ivan@Example.com"
+  on_destroy_forward_to = ["manager@example.com"]
+}
+`, endpoint)
 }
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
@@ -29,16 +30,32 @@ func TestAccUserResourceWith2FA(t *testing.T) {
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("user_name"), knownvalue.StringExact("charlie")),
 					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("require_two_factor_authentication"), knownvalue.Bool(true)),
-					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods").AtSliceIndex(0).AtMapKey("type"), knownvalue.StringExact("email")),
-					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods").AtSliceIndex(0).AtMapKey("target"), knownvalue.StringExact("charlie@recovery.example.com")),
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods").AtMapKey("charlie@recovery.example.com").AtMapKey("type"), knownvalue.StringExact("email")),
 				},
 			},
 			// Update: Add another password reset method
 			{
-				Config: testAccUserResourceWith2FAConfigUpdated(ts.URL),
+				Config: testAccUserResourceWith2FAConfigUpdated(ts.URL, "+15551234567", "Recovery phone"),
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("require_two_factor_authentication"), knownvalue.Bool(true)),
 				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "charlie", "charlie@recovery.example.com", "+15551234567"),
+			},
+			// Update: Change only a description, the method is updated in place
+			{
+				Config: testAccUserResourceWith2FAConfigUpdated(ts.URL, "+15551234567", "Work phone"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods").AtMapKey("+15551234567").AtMapKey("description"), knownvalue.StringExact("Work phone")),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "charlie", "charlie@recovery.example.com", "+15551234567"),
+			},
+			// Update: Respell the phone number, the method is kept
+			{
+				Config: testAccUserResourceWith2FAConfigUpdated(ts.URL, "+1 555 123 4567", "Work phone"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("purelymail_user.test", tfjsonpath.New("password_reset_methods").AtMapKey("+1 555 123 4567").AtMapKey("type"), knownvalue.StringExact("phone")),
+				},
+				Check: testAccCheckPasswordResetTargets(ts.URL, "charlie", "charlie@recovery.example.com", "+15551234567"),
 			},
 			// Update: Disable 2FA
 			{
@@ -64,22 +81,21 @@ resource "purelymail_user" "test" {
   password_wo                       = "secure-password-123"
   require_two_factor_authentication = true
 
-  password_reset_methods = [
-    {
+  password_reset_methods = {
+    "charlie@recovery.example.com" = {
       type            = "email"
-      target          = "charlie@recovery.example.com"
       description     = "Primary recovery email"
       allow_mfa_reset = true
     }
-  ]
+  }
 }
 `
 }
 
-func testAccUserResourceWith2FAConfigUpdated(endpoint string) string {
-	return `
+func testAccUserResourceWith2FAConfigUpdated(endpoint string, phone string, phoneDescription string) string {
+	return fmt.Sprintf(`
 provider "purelymail" {
-  endpoint  = "` + endpoint + `"
+  endpoint  = %[1]q
   api_token = "test-token"
 }
 
@@ -88,22 +104,20 @@ resource "purelymail_user" "test" {
   password_wo                       = "secure-password-123"
   require_two_factor_authentication = true
 
-  password_reset_methods = [
-    {
+  password_reset_methods = {
+    "charlie@recovery.example.com" = {
       type            = "email"
-      target          = "charlie@recovery.example.com"
       description     = "Primary recovery email"
       allow_mfa_reset = true
-    },
-    {
+    }
+    %[2]q = {
       type            = "phone"
-      target          = "+15551234567"
-      description     = "Recovery phone"
+      description     = %[3]q
       allow_mfa_reset = false
     }
-  ]
+  }
 }
-`
+`, endpoint, phone, phoneDescription)
 }
 
 func testAccUserResourceWith2FAConfigNo2FA(endpoint string) string {
@@ -118,14 +132,13 @@ resource "purelymail_user" "test" {
   password_wo                       = "secure-password-123"
   require_two_factor_authentication = false
 
-  password_reset_methods = [
-    {
+  password_reset_methods = {
+    "charlie@recovery.example.com" = {
       type            = "email"
-      target          = "charlie@recovery.example.com"
       description     = "Primary recovery email"
       allow_mfa_reset = true
     }
-  ]
+  }
 }
 `
 }
@@ -0,0 +1,327 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &UserTwoFactorResource{}
+var _ resource.ResourceWithImportState = &UserTwoFactorResource{}
+var _ resource.ResourceWithModifyPlan = &UserTwoFactorResource{}
+var _ resource.ResourceWithIdentity = &UserTwoFactorResource{}
+
+func NewUserTwoFactorResource() resource.Resource {
+	return &UserTwoFactorResource{}
+}
+
+// UserTwoFactorResource defines the resource implementation.
+type UserTwoFactorResource struct {
+	client *api.Client
+}
+
+// UserTwoFactorResourceModel describes the resource data model.
+type UserTwoFactorResourceModel struct {
+	UserName MailboxNameValue `tfsdk:"user_name"`
+	Id       types.String     `tfsdk:"id"`
+	Timeouts timeouts.Value   `tfsdk:"timeouts"`
+}
+
+// UserTwoFactorResourceIdentityModel describes the resource identity.
+type UserTwoFactorResourceIdentityModel struct {
+	UserName types.String `tfsdk:"user_name"`
+}
+
+// userTwoFactorIdentity returns the identity of the resource, with the domain
+// of the user name normalized.
+func userTwoFactorIdentity(data *UserTwoFactorResourceModel) UserTwoFactorResourceIdentityModel {
+	return UserTwoFactorResourceIdentityModel{
+		UserName: types.StringValue(normalizedOrRaw(normalizeMailboxName, data.UserName.ValueString())),
+	}
+}
+
+func (r *UserTwoFactorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_two_factor"
+}
+
+func (r *UserTwoFactorResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"user_name": identityschema.StringAttribute{
+				Description:       "The username that requires two-factor authentication.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+func (r *UserTwoFactorResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Requires two-factor authentication for a Purelymail user. Use this instead of `require_two_factor_authentication` on `purelymail_user` when the user's password reset methods are managed with `purelymail_password_reset_method` resources, and add those resources to `depends_on`. 2FA is then enabled after the reset methods are created, and disabled before they are destroyed. Leave `require_two_factor_authentication` unset on the user.",
+
+		Attributes: map[string]schema.Attribute{
+			"user_name": schema.StringAttribute{
+				MarkdownDescription: "The username to require two-factor authentication for.",
+				CustomType:          MailboxNameType{},
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessSemanticallyEqual(MailboxNameType{}),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The identifier for this resource (same as user_name).",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			// Updates make no API calls
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Read: true, Delete: true}),
+		},
+	}
+}
+
+func (r *UserTwoFactorResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *api.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UserTwoFactorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data UserTwoFactorResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "create", data.Timeouts.Create, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	// Purelymail refuses to enable 2FA for users without a way to reset it
+	methods, err := listPasswordResetMethods(ctx, r.client, data.UserName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read password reset methods: %s", err))
+		return
+	}
+	if len(methods) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user_name"),
+			"Missing Password Reset Method",
+			fmt.Sprintf("User %s has no password reset methods, and two-factor authentication requires at least one. "+
+				"Add the user's purelymail_password_reset_method resources to depends_on, so they are created first.", data.UserName.ValueString()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.setTwoFactor(ctx, data.UserName.ValueString(), true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.UserName.StringValue
+
+	tflog.Trace(ctx, "created purelymail_user_two_factor resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userTwoFactorIdentity(&data))...)
+}
+
+func (r *UserTwoFactorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data UserTwoFactorResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "read", data.Timeouts.Read, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	enabled, err := r.readTwoFactor(ctx, data.UserName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read user: %s", err))
+		return
+	}
+
+	if !enabled {
+		// The user was deleted or 2FA was turned off outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Imported resources only have user_name
+	data.Id = data.UserName.StringValue
+
+	tflog.Trace(ctx, "read purelymail_user_two_factor resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userTwoFactorIdentity(&data))...)
+}
+
+func (r *UserTwoFactorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state UserTwoFactorResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the spelling of user_name can change, so there is nothing to send
+	keepPriorSpelling(&data.UserName, state.UserName)
+	data.Id = state.Id
+
+	tflog.Trace(ctx, "updated purelymail_user_two_factor resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, userTwoFactorIdentity(&data))...)
+}
+
+func (r *UserTwoFactorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data UserTwoFactorResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, finish, diags := startOperation(ctx, "delete", data.Timeouts.Delete, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer finish(&resp.Diagnostics)
+
+	resp.Diagnostics.Append(r.setTwoFactor(ctx, data.UserName.ValueString(), false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "deleted purelymail_user_two_factor resource")
+}
+
+func (r *UserTwoFactorResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state UserTwoFactorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.UserName.Equal(state.UserName) {
+		resp.Diagnostics.Append(warnUnknownUserDomain(ctx, r.client, path.Root("user_name"), plan.UserName.StringValue)...)
+	}
+}
+
+func (r *UserTwoFactorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Use the username as the import identifier
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("user_name"), path.Root("user_name"), req, resp)
+}
+
+// setTwoFactor turns the user's 2FA requirement on or off. Turning it off for
+// a user that no longer exists succeeds.
+func (r *UserTwoFactorResource) setTwoFactor(ctx context.Context, userName string, enabled bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if enabled {
+		setStep(ctx, "enable two-factor authentication")
+	} else {
+		setStep(ctx, "disable two-factor authentication")
+	}
+
+	httpResp, err := r.client.ModifyUser(ctx, api.ModifyUserJSONRequestBody{
+		UserName:                       userName,
+		RequireTwoFactorAuthentication: &enabled,
+	})
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to modify user: %s", err))
+		return diags
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusNotFound && !enabled {
+		return diags
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		diags.AddError("API Error", fmt.Sprintf("Failed to modify user (status: %d)", httpResp.StatusCode))
+	}
+
+	return diags
+}
+
+// readTwoFactor reports whether the user requires 2FA. A user that doesn't
+// exist doesn't.
+func (r *UserTwoFactorResource) readTwoFactor(ctx context.Context, userName string) (bool, error) {
+	setStep(ctx, "read user")
+	httpResp, err := r.client.GetUser(ctx, api.GetUserJSONRequestBody{
+		UserName: userName,
+	})
+	if err != nil {
+		return false, fmt.Errorf("unable to read user: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("API returned status %d", httpResp.StatusCode)
+	}
+
+	var getUserResp api.GetUserResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&getUserResp); err != nil {
+		return false, fmt.Errorf("unable to decode user response: %w", err)
+	}
+
+	if getUserResp.Result == nil || getUserResp.Result.RequireTwoFactorAuthentication == nil {
+		return false, nil
+	}
+
+	return *getUserResp.Result.RequireTwoFactorAuthentication, nil
+}
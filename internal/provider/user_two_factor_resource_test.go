@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api"
+	"github.com/stargrid-systems/terraform-provider-purelymail/internal/api/mock"
+)
+
+func TestAccUserTwoFactorResource(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// 2FA can't be enabled before the user has a password reset method
+			{
+				Config:      testAccUserTwoFactorResourceConfig(ts.URL, false),
+				ExpectError: regexp.MustCompile(`Missing Password Reset Method`),
+			},
+			// Create and Read testing
+			{
+				Config: testAccUserTwoFactorResourceConfig(ts.URL, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("purelymail_user_two_factor.test", "user_name", "ivan@example.com"),
+					resource.TestCheckResourceAttr("purelymail_user_two_factor.test", "id", "ivan@example.com"),
+				),
+			},
+			// The user sees 2FA enabled without planning to change it
+			{
+				RefreshState: true,
+				Check:        resource.TestCheckResourceAttr("purelymail_user.test", "require_two_factor_authentication", "true"),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "purelymail_user_two_factor.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "ivan@example.com",
+			},
+			// Delete testing automatically occurs in TestCase, turning 2FA off
+			// before the password reset method is deleted
+		},
+	})
+}
+
+func TestAccUserTwoFactorResourceIdentity(t *testing.T) {
+	// Create mock server using generated ServerInterface
+	mockServer := mock.NewServer()
+	handler := api.Handler(mockServer)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserTwoFactorResourceConfig(ts.URL, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("purelymail_user_two_factor.test", map[string]knownvalue.Check{
+						"user_name": knownvalue.StringExact("ivan@example.com"),
+					}),
+				},
+			},
+			{
+				ResourceName:    "purelymail_user_two_factor.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs
+		},
+	})
+}
+
+func testAccUserTwoFactorResourceConfig(endpoint string, withMethod bool) string {
+	method := ""
+	dependsOn := ""
+	if withMethod {
+		method = `
+resource "purelymail_password_reset_method" "test" {
+  user_name = purelymail_user.test.user_name
+  type      = "email"
+  target    = "ivan@recovery.example.com"
+}
+`
+		dependsOn = "depends_on = [purelymail_password_reset_method.test]"
+	}
+
+	return fmt.Sprintf(`
+provider "purelymail" {
+  endpoint  = %[1]q
+  api_token = "test-token"
+}
+
+resource "purelymail_user" "test" {
+  user_name                   = "ivan@example.com"
+  password_reset_methods_mode = "ignore"
+}
+%[2]s
+resource "purelymail_user_two_factor" "test" {
+  user_name = purelymail_user.test.user_name
+
+  %[3]s
+}
+`, endpoint, method, dependsOn)
+}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Ensure provider defined validators fully satisfy framework interfaces.
+var _ validator.String = durationValidator{}
+var _ validator.String = pgpKeyValidator{}
+
+// durationValidator validates that a string is a positive Go duration like "30s" or "15m".
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a positive duration such as \"30s\" or \"15m\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %s %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// pgpKeyValidator validates that a string is an ASCII-armored PGP public key
+// with an encryption key, or a keybase: reference. Keybase keys are only
+// fetched and checked when they are used.
+type pgpKeyValidator struct{}
+
+func (v pgpKeyValidator) Description(ctx context.Context) string {
+	return "value must be an ASCII-armored PGP public key or a keybase user such as \"keybase:alice\""
+}
+
+func (v pgpKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pgpKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if userName, ok := strings.CutPrefix(value, keybasePrefix); ok {
+		if userName == "" {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Invalid PGP Key",
+				fmt.Sprintf("Attribute %s %s, got a keybase reference without a user name", req.Path, v.Description(ctx)),
+			)
+		}
+		return
+	}
+
+	if _, err := parsePGPKey(value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid PGP Key",
+			fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err),
+		)
+	}
+}